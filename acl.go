@@ -0,0 +1,21 @@
+package efs
+
+// WithPrivateACL replaces the inherited access control list of the extraction
+// root (or the extracted file, for ExtractFile) on Windows with an explicit,
+// protected DACL that grants full control to the current user only. Entries
+// extracted below the root inherit it. Unix mode bits barely matter on
+// Windows, and the inherited ACL of a shared temp or program directory is
+// often too permissive. PermsStrict enables it as well. It relies on icacls
+// and is a no-op on other platforms.
+func WithPrivateACL() Option {
+	return func(o *options) { o.privateACL = true }
+}
+
+// applyPrivateACL restricts path to the current user if WithPrivateACL was
+// given.
+func (o *options) applyPrivateACL(path string, isDir bool) error {
+	if !o.privateACL {
+		return nil
+	}
+	return restrictToCurrentUser(path, isDir)
+}
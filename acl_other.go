@@ -0,0 +1,8 @@
+//go:build !windows
+
+package efs
+
+// restrictToCurrentUser is a no-op outside Windows; use mode bits instead.
+func restrictToCurrentUser(path string, isDir bool) error {
+	return nil
+}
@@ -0,0 +1,40 @@
+package efs
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// restrictToCurrentUser gives path a protected DACL with a single entry for
+// the current user; directories pass it on to their contents.
+func restrictToCurrentUser(path string, isDir bool) error {
+	sid, err := currentUserSID()
+	if err != nil {
+		return fmt.Errorf("look up current user: %w", err)
+	}
+	grant := "*" + sid + ":F"
+	if isDir {
+		grant = "*" + sid + ":(OI)(CI)F"
+	}
+	out, err := exec.Command("icacls", path, "/inheritance:r", "/grant:r", grant).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("icacls %s: %w: %s", path, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// currentUserSID returns the string SID of the user running the process.
+func currentUserSID() (string, error) {
+	token, err := syscall.OpenCurrentProcessToken()
+	if err != nil {
+		return "", err
+	}
+	defer token.Close()
+	user, err := token.GetTokenUser()
+	if err != nil {
+		return "", err
+	}
+	return user.User.Sid.String()
+}
@@ -0,0 +1,29 @@
+package efs
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithPrivateACL(t *testing.T) {
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+	e, err := Extract(mem, ".", "acl", t.TempDir(), WithPrivateACL())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+
+	if data, err := os.ReadFile(e.Path("a.txt")); err != nil || string(data) != "A" {
+		t.Fatalf("expected owner to keep access, got %q, %v", data, err)
+	}
+	out, err := exec.Command("icacls", e.Dir()).CombinedOutput()
+	if err != nil {
+		t.Fatalf("icacls: %v: %s", err, out)
+	}
+	if strings.Contains(string(out), `BUILTIN\Users`) {
+		t.Errorf("expected inherited entries removed, got:\n%s", out)
+	}
+}
@@ -0,0 +1,72 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// WithAtomic makes ExtractTo and Plan.Apply build the tree in a hidden
+// staging directory next to the destination and rename it into place only
+// once extraction has succeeded, so consumers watching the destination never
+// see a partially populated tree. If the destination already exists, it is
+// replaced as a whole: the old tree is moved aside, the new one renamed in,
+// and the old one removed. Existing content is therefore not merged and
+// WithConflict has no effect. On failure the staging directory is removed and
+// the destination is left untouched. Should the final rename cross file
+// systems (EXDEV), the tree is copied and synced next to the destination and
+// swapped in from there, with the same guarantees.
+func WithAtomic() Option {
+	return func(o *options) { o.atomic = true }
+}
+
+// stagingDir creates the hidden staging directory for an atomic extraction
+// into dst, on the same file system so the final rename cannot cross devices.
+func (o *options) stagingDir(dst string) (string, error) {
+	parent := filepath.Dir(dst)
+	if err := os.MkdirAll(parent, o.dirPerm()); err != nil {
+		return "", err
+	}
+	return mkdirUnique(parent, "."+filepath.Base(dst)+".efs-staging-", o.dirPerm())
+}
+
+// commitStaging moves the completed staging directory to dst, replacing any
+// existing tree at dst.
+func commitStaging(staging, dst string) error {
+	if _, err := os.Lstat(dst); errors.Is(err, fs.ErrNotExist) {
+		return os.Rename(staging, dst)
+	}
+	old := uniqueName(filepath.Dir(dst), "."+filepath.Base(dst)+".efs-old-")
+	if err := os.Rename(dst, old); err != nil {
+		return err
+	}
+	if err := os.Rename(staging, dst); err != nil {
+		_ = os.Rename(old, dst) // Put the previous tree back
+		return err
+	}
+	return os.RemoveAll(old)
+}
+
+// mkdirUnique creates a new directory in parent whose name starts with prefix
+// followed by a random suffix. Unlike os.MkdirTemp, perm is honored (subject
+// to the umask).
+func mkdirUnique(parent, prefix string, perm fs.FileMode) (string, error) {
+	for try := 0; ; try++ {
+		name := uniqueName(parent, prefix)
+		err := os.Mkdir(name, perm)
+		if err == nil {
+			return name, nil
+		}
+		if !errors.Is(err, fs.ErrExist) || try >= 10000 {
+			return "", err
+		}
+	}
+}
+
+// uniqueName returns a path in parent consisting of prefix and a random suffix.
+func uniqueName(parent, prefix string) string {
+	return filepath.Join(parent, prefix+strconv.FormatUint(uint64(rand.Uint32()), 10))
+}
@@ -0,0 +1,60 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAtomicExtractTo(t *testing.T) {
+	parent := t.TempDir()
+	dst := filepath.Join(parent, "assets")
+	mem := fstest.MapFS{"new.txt": {Data: []byte("new")}}
+
+	if err := ExtractTo(mem, ".", dst, WithAtomic()); err != nil {
+		t.Fatalf("ExtractTo error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "new.txt")); err != nil {
+		t.Fatalf("expected new.txt: %v", err)
+	}
+
+	// A second atomic extraction replaces the tree as a whole.
+	os.WriteFile(filepath.Join(dst, "stale.txt"), []byte("old"), 0o644)
+	if err := ExtractTo(mem, ".", dst, WithAtomic()); err != nil {
+		t.Fatalf("ExtractTo error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "stale.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected stale.txt to be gone, got err=%v", err)
+	}
+
+	// No staging or backup directories are left behind.
+	entries, _ := os.ReadDir(parent)
+	if len(entries) != 1 {
+		t.Errorf("expected only %s in parent, got %d entries", dst, len(entries))
+	}
+}
+
+func TestAtomicFailureLeavesDestinationUntouched(t *testing.T) {
+	parent := t.TempDir()
+	dst := filepath.Join(parent, "assets")
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dst, "keep.txt"), []byte("keep"), 0o644)
+
+	bad := badFS{base: fstest.MapFS{"a.txt": {Data: []byte("A")}, "b.txt": {Data: []byte("B")}}, fail: "b.txt"}
+	if err := ExtractTo(bad, ".", dst, WithAtomic()); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := os.Stat(filepath.Join(dst, "a.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected no partial content, got err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "keep.txt")); err != nil {
+		t.Errorf("expected existing content kept: %v", err)
+	}
+	entries, _ := os.ReadDir(parent)
+	if len(entries) != 1 {
+		t.Errorf("expected staging dir removed, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,67 @@
+package efs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// WithAuditLog appends one JSON line per extracted file to w, recording the
+// timestamp, source path, destination path, size, digest (SHA-256 unless
+// WithHash selects another algorithm), result ("ok" or the error message) and
+// extraction ID. Failed writes are recorded too, so compliance pipelines can
+// account for every file efs attempted to put on disk. A failure to write the
+// audit record itself aborts the extraction. Names are subject to
+// WithRedaction. Writes to w are serialized.
+func WithAuditLog(w io.Writer) Option {
+	return func(o *options) { o.audit = &auditLog{w: w} }
+}
+
+// auditRecord is the JSON shape of a single audit log line.
+type auditRecord struct {
+	Time   string `json:"time"`
+	Source string `json:"source"`
+	Dest   string `json:"dest"`
+	Size   int64  `json:"size"`
+	Digest string `json:"digest,omitempty"`
+	Result string `json:"result"`
+	ID     string `json:"id,omitempty"` // Extraction ID (see WithExtractionID)
+}
+
+type auditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// recordFile writes the audit line for src extracted to dst. rel is the
+// destination name relative to the extraction root, used for redaction.
+func (o *options) recordFile(src, rel, dst string, size int64, digest string, writeErr error) error {
+	if o.audit == nil {
+		return nil
+	}
+	rec := auditRecord{
+		Time:   time.Now().UTC().Format(time.RFC3339Nano),
+		Source: o.display(src),
+		Dest:   o.scrub(dst, rel),
+		Size:   size,
+		Digest: digest,
+		Result: "ok",
+		ID:     o.id,
+	}
+	if writeErr != nil {
+		rec.Result = o.scrub(writeErr.Error(), src, rel)
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	o.audit.mu.Lock()
+	defer o.audit.mu.Unlock()
+	if _, err := o.audit.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
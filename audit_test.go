@@ -0,0 +1,51 @@
+package efs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAuditLog(t *testing.T) {
+	mem := fstest.MapFS{
+		"root/a.txt":     {Data: []byte("A")},
+		"root/sub/b.txt": {Data: []byte("BB")},
+	}
+
+	var buf bytes.Buffer
+	dir, cleanup, err := ExtractToTemp(mem, "root", "audit", t.TempDir(), WithAuditLog(&buf))
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+
+	var records []auditRecord
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var rec auditRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid audit line %q: %v", sc.Text(), err)
+		}
+		records = append(records, rec)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 audit records (files only), got %d", len(records))
+	}
+
+	rec := records[1]
+	if rec.Source != "root/sub/b.txt" {
+		t.Errorf("expected source root/sub/b.txt, got %q", rec.Source)
+	}
+	if rec.Dest != filepath.Join(dir, "sub", "b.txt") {
+		t.Errorf("unexpected dest %q", rec.Dest)
+	}
+	if rec.Size != 2 || rec.Digest != dataDigest(SHA256, []byte("BB")) || rec.Result != "ok" {
+		t.Errorf("unexpected record %+v", rec)
+	}
+	if rec.Time == "" {
+		t.Error("expected timestamp")
+	}
+}
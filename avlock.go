@@ -0,0 +1,61 @@
+package efs
+
+import (
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Retry schedule of OpenExtracted and StartExtracted: about three seconds in
+// total, long enough for an on-access virus scan of a freshly written file.
+var (
+	avAttempts = 8
+	avBackoff  = 25 * time.Millisecond
+)
+
+// OpenExtracted opens the file at path for reading like os.Open, retrying for
+// a few seconds while the file is briefly locked. Right after extraction,
+// Windows Defender and other virus scanners open new files exclusively to
+// scan them, so an immediate open fails with a sharing violation or "access
+// denied" that is gone moments later; on Unix the equivalent is a transient
+// ETXTBSY or EBUSY. Other errors, such as a missing file, are returned at
+// once; a lasting access denial is returned after the retries.
+func OpenExtracted(path string) (*os.File, error) {
+	var f *os.File
+	err := retryLocked(func() (err error) {
+		f, err = os.Open(path)
+		return err
+	})
+	return f, err
+}
+
+// StartExtracted starts the command returned by build, retrying with a fresh
+// command from build while starting fails because a virus scanner still
+// holds the just-extracted executable (see OpenExtracted). An exec.Cmd
+// cannot be started twice, so build is called once per attempt and must
+// return a new, unstarted command each time, including new pipes if any.
+//
+// Example:
+//
+//	cmd, err := efs.StartExtracted(func() *exec.Cmd {
+//		return exec.Command(ex.Executable("bin/tool"), "--serve")
+//	})
+func StartExtracted(build func() *exec.Cmd) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+	err := retryLocked(func() error {
+		cmd = build()
+		return cmd.Start()
+	})
+	return cmd, err
+}
+
+// retryLocked runs op until it succeeds, fails with an error other than a
+// scanner lock, or the attempts are used up.
+func retryLocked(op func() error) error {
+	err := op()
+	for i := 1; i < avAttempts && err != nil && avLocked(err); i++ {
+		time.Sleep(avBackoff << (i - 1))
+		err = op()
+	}
+	return err
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package efs
+
+// avLocked reports whether err may come from a process briefly holding a new
+// file, such as a scanner or the writer of an executable (ETXTBSY).
+func avLocked(err error) bool {
+	return isTransient(err)
+}
@@ -0,0 +1,53 @@
+//go:build unix
+
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestRetryLocked(t *testing.T) {
+	defer func(n int) { avAttempts = n }(avAttempts)
+	avAttempts = 4
+
+	calls := 0
+	err := retryLocked(func() error {
+		calls++
+		if calls < 3 {
+			return &fs.PathError{Op: "open", Path: "tool", Err: syscall.ETXTBSY}
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("expected success on the third attempt, got %v after %d", err, calls)
+	}
+
+	calls = 0
+	err = retryLocked(func() error {
+		calls++
+		return fs.ErrNotExist
+	})
+	if !errors.Is(err, fs.ErrNotExist) || calls != 1 {
+		t.Errorf("expected a missing file to fail at once, got %v after %d", err, calls)
+	}
+}
+
+func TestOpenExtracted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	if err := os.WriteFile(path, []byte("A"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := OpenExtracted(path)
+	if err != nil {
+		t.Fatalf("OpenExtracted error: %v", err)
+	}
+	f.Close()
+	if _, err := OpenExtracted(path + ".missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected ErrNotExist, got %v", err)
+	}
+}
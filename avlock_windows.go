@@ -0,0 +1,13 @@
+package efs
+
+import (
+	"errors"
+	"syscall"
+)
+
+// avLocked reports whether err may come from a virus scanner holding a new
+// file: a sharing or lock violation, or access denied while the scanner has
+// the file open.
+func avLocked(err error) bool {
+	return isTransient(err) || errors.Is(err, syscall.ERROR_ACCESS_DENIED)
+}
@@ -0,0 +1,102 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// defaultBase is the process-wide base directory set with SetDefaultBaseDir
+// and SetBaseDirResolver.
+var defaultBase struct {
+	mu      sync.RWMutex
+	dir     string
+	resolve func() string
+}
+
+// SetDefaultBaseDir sets the base directory used by every extraction that
+// specifies neither a tempDir argument nor WithTempDir, so applications can
+// configure it once at startup instead of threading it through every call.
+// An empty path restores the built-in default, os.TempDir(). It is safe to
+// call concurrently with extractions; running extractions are not affected.
+//
+// Earlier versions created temp entries in the current working directory;
+// SetDefaultBaseDir(".") restores that behavior.
+func SetDefaultBaseDir(path string) {
+	defaultBase.mu.Lock()
+	defer defaultBase.mu.Unlock()
+	defaultBase.dir = path
+}
+
+// SetBaseDirResolver installs fn to supply the default base directory when
+// none was set with SetDefaultBaseDir. It is called on every extraction that
+// needs the default, so it can follow platform state. This is the hook for
+// hosts where neither the working directory nor os.TempDir() is writable,
+// such as gomobile apps on Android and iOS, which pass their platform cache
+// directory:
+//
+//	efs.SetBaseDirResolver(func() string { return cacheDir })
+//
+// If fn returns "", os.TempDir() is used. A nil fn removes the resolver.
+func SetBaseDirResolver(fn func() string) {
+	defaultBase.mu.Lock()
+	defer defaultBase.mu.Unlock()
+	defaultBase.resolve = fn
+}
+
+// DefaultBaseDir returns the base directory used when none is specified: the
+// path set with SetDefaultBaseDir, else the result of the resolver installed
+// with SetBaseDirResolver, else os.TempDir().
+func DefaultBaseDir() string {
+	defaultBase.mu.RLock()
+	dir, resolve := defaultBase.dir, defaultBase.resolve
+	defaultBase.mu.RUnlock()
+	if dir != "" {
+		return dir
+	}
+	if resolve != nil {
+		if dir := resolve(); dir != "" {
+			return dir
+		}
+	}
+	// The working directory is often read-only or a source checkout; the
+	// system temp dir is the conventional home for throwaway files.
+	return os.TempDir()
+}
+
+// WithTempDir sets the base directory in which temporary directories and
+// files are created. It is the option form of the tempDir parameter; a
+// non-empty tempDir argument takes precedence over it.
+func WithTempDir(dir string) Option {
+	return func(o *options) { o.tempDir = dir }
+}
+
+// WithRuntimeDir creates temporary entries in $XDG_RUNTIME_DIR, the per-user
+// runtime directory of the XDG Base Directory specification. It is private
+// to the user, usually RAM-backed and removed at logout, which suits
+// per-session runtime data such as sockets, helper binaries and generated
+// configuration. If XDG_RUNTIME_DIR is unset or not an absolute path (as is
+// common on Windows and macOS), the usual base directory is used. An
+// explicit tempDir argument, WithTempDir or WithStageNear wins; it takes
+// precedence over WithPreferTmpfs and DefaultBaseDir.
+func WithRuntimeDir() Option {
+	return func(o *options) { o.runtimeDir = true }
+}
+
+// runtimeDir returns $XDG_RUNTIME_DIR if it is set to an absolute path.
+func runtimeDir() (string, bool) {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	return dir, dir != "" && filepath.IsAbs(dir)
+}
+
+// baseDir resolves where temporary entries are created: the explicit tempDir
+// argument, then WithTempDir, then DefaultBaseDir.
+func (o *options) baseDir(tempDir string) string {
+	if tempDir != "" {
+		return tempDir
+	}
+	if o.tempDir != "" {
+		return o.tempDir
+	}
+	return DefaultBaseDir()
+}
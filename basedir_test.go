@@ -0,0 +1,136 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExtractToTempOptWithTempDir(t *testing.T) {
+	base := t.TempDir()
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+
+	dir, cleanup, err := ExtractToTempOpt(mem, ".", "opt", WithTempDir(base))
+	if err != nil {
+		t.Fatalf("ExtractToTempOpt error: %v", err)
+	}
+	defer cleanup()
+
+	if filepath.Dir(dir) != base {
+		t.Errorf("expected dir in %q, got %q", base, dir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil {
+		t.Fatalf("expected a.txt: %v", err)
+	}
+}
+
+func TestSetDefaultBaseDir(t *testing.T) {
+	base := t.TempDir()
+	SetDefaultBaseDir(base)
+	defer SetDefaultBaseDir("")
+
+	if got := DefaultBaseDir(); got != base {
+		t.Fatalf("expected DefaultBaseDir %q, got %q", base, got)
+	}
+
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+	dir, cleanup, err := ExtractToTemp(mem, ".", "global", "")
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+	if filepath.Dir(dir) != base {
+		t.Errorf("expected dir in %q, got %q", base, dir)
+	}
+
+	// An explicit tempDir still wins over the global default.
+	other := t.TempDir()
+	dir2, cleanup2, err := ExtractToTemp(mem, ".", "global", other)
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup2()
+	if filepath.Dir(dir2) != other {
+		t.Errorf("expected dir in %q, got %q", other, dir2)
+	}
+}
+
+func TestDefaultBaseDirIsOSTempDir(t *testing.T) {
+	if got := DefaultBaseDir(); got != os.TempDir() {
+		t.Fatalf("expected default base %q, got %q", os.TempDir(), got)
+	}
+
+	// The compatibility knob restores the working-directory behavior.
+	SetDefaultBaseDir(".")
+	defer SetDefaultBaseDir("")
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+	dir, cleanup, err := ExtractToTemp(mem, ".", "legacy", "")
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+	wd, _ := os.Getwd()
+	if filepath.Dir(dir) != wd {
+		t.Errorf("expected dir in %q, got %q", wd, dir)
+	}
+}
+
+func TestSetBaseDirResolver(t *testing.T) {
+	cache := t.TempDir()
+	SetBaseDirResolver(func() string { return cache })
+	defer SetBaseDirResolver(nil)
+
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+	file, cleanup, err := ExtractFile(mem, "a.txt", "resolved", "")
+	if err != nil {
+		t.Fatalf("ExtractFile error: %v", err)
+	}
+	defer cleanup()
+	if filepath.Dir(file) != cache {
+		t.Errorf("expected file in %q, got %q", cache, file)
+	}
+
+	// SetDefaultBaseDir takes precedence over the resolver.
+	fixed := t.TempDir()
+	SetDefaultBaseDir(fixed)
+	defer SetDefaultBaseDir("")
+	if got := DefaultBaseDir(); got != fixed {
+		t.Errorf("expected %q, got %q", fixed, got)
+	}
+
+	SetDefaultBaseDir("")
+	SetBaseDirResolver(func() string { return "" })
+	if got := DefaultBaseDir(); got != os.TempDir() {
+		t.Errorf("expected os.TempDir() fallback, got %q", got)
+	}
+}
+
+func TestWithRuntimeDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+	file, cleanup, err := ExtractFile(mem, "a.txt", "runtime", "", WithRuntimeDir())
+	if err != nil {
+		t.Fatalf("ExtractFile error: %v", err)
+	}
+	defer cleanup()
+	if filepath.Dir(file) != dir {
+		t.Errorf("expected file in %q, got %q", dir, file)
+	}
+
+	// A relative XDG_RUNTIME_DIR is ignored, as the specification requires.
+	t.Setenv("XDG_RUNTIME_DIR", "relative")
+	base := t.TempDir()
+	SetDefaultBaseDir(base)
+	defer SetDefaultBaseDir("")
+	file, cleanup2, err := ExtractFile(mem, "a.txt", "runtime", "", WithRuntimeDir())
+	if err != nil {
+		t.Fatalf("ExtractFile error: %v", err)
+	}
+	defer cleanup2()
+	if filepath.Dir(file) != base {
+		t.Errorf("expected file in default base %q, got %q", base, file)
+	}
+}
@@ -0,0 +1,24 @@
+package efs
+
+// BindReadOnly bind-mounts dir read-only over itself, so nothing in the
+// process tree (or on the host) can modify the extracted assets afterwards.
+// It requires Linux and CAP_SYS_ADMIN; elsewhere it returns an error matching
+// errors.ErrUnsupported. The returned unmount function lifts the protection
+// again and must be called before dir can be removed.
+func BindReadOnly(dir string) (unmount func() error, err error) {
+	return bindReadOnly(dir)
+}
+
+// BindReadOnly protects the extracted directory with a read-only bind mount
+// (see the package-level BindReadOnly). Cleanup unmounts it before removing
+// the directory.
+func (e *Extraction) BindReadOnly() error {
+	unmount, err := bindReadOnly(e.dir)
+	if err != nil {
+		return destErr(e.dir, err)
+	}
+	e.mu.Lock()
+	e.release = append(e.release, unmount)
+	e.mu.Unlock()
+	return nil
+}
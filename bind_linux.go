@@ -0,0 +1,23 @@
+package efs
+
+import (
+	"os"
+	"syscall"
+)
+
+func bindReadOnly(dir string) (func() error, error) {
+	if err := syscall.Mount(dir, dir, "", syscall.MS_BIND, ""); err != nil {
+		return nil, &os.PathError{Op: "bind mount", Path: dir, Err: err}
+	}
+	// The read-only flag only takes effect on a remount of the bind mount.
+	if err := syscall.Mount("", dir, "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY, ""); err != nil {
+		_ = syscall.Unmount(dir, 0)
+		return nil, &os.PathError{Op: "remount read-only", Path: dir, Err: err}
+	}
+	return func() error {
+		if err := syscall.Unmount(dir, 0); err != nil {
+			return &os.PathError{Op: "unmount", Path: dir, Err: err}
+		}
+		return nil
+	}, nil
+}
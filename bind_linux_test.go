@@ -0,0 +1,34 @@
+package efs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExtractionBindReadOnly(t *testing.T) {
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+	e, err := Extract(mem, ".", "bind", t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+
+	if err := e.BindReadOnly(); errors.Is(err, syscall.EPERM) {
+		t.Skip("bind mounts need CAP_SYS_ADMIN")
+	} else if err != nil {
+		t.Fatalf("BindReadOnly error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(e.Dir(), "a.txt"), []byte("X"), 0o644); !errors.Is(err, syscall.EROFS) {
+		t.Errorf("expected EROFS writing to protected tree, got %v", err)
+	}
+	if err := e.Cleanup(); err != nil {
+		t.Fatalf("Cleanup error: %v", err)
+	}
+	if _, err := os.Stat(e.Dir()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected directory removed, got %v", err)
+	}
+}
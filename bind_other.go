@@ -0,0 +1,12 @@
+//go:build !linux
+
+package efs
+
+import (
+	"errors"
+	"os"
+)
+
+func bindReadOnly(dir string) (func() error, error) {
+	return nil, &os.PathError{Op: "bind mount", Path: dir, Err: errors.ErrUnsupported}
+}
@@ -0,0 +1,118 @@
+package efs
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// WithCacheDir sets the directory ExtractCached keeps its extractions in,
+// instead of "efs" in the per-user cache directory (os.UserCacheDir).
+func WithCacheDir(dir string) Option {
+	return func(o *options) { o.cacheDir = dir }
+}
+
+// ExtractCached extracts root of fsys into a stable directory named
+// "<name>-<key>" in the per-user cache directory and returns its absolute
+// path, where key is derived from a hash of the paths and content to
+// extract (SHA-256 unless WithHash selects another algorithm). If that
+// directory already exists, from an earlier run of the program or another
+// process, it is reused as is; otherwise the tree is extracted next to it
+// and renamed into place once complete, so a crash never leaves a partial
+// tree under the final name. A program that restarts with unchanged assets
+// thus skips re-copying them, while a new build gets a fresh directory.
+//
+// Computing the key reads every source file, which is much cheaper than
+// writing the tree but not free. The directory is not removed by efs;
+// earlier versions stay behind until deleted. name must satisfy the rules
+// for tempPrefix (see ErrInvalidPrefix). opts are the options of Extract,
+// which ExtractCached calls with WithContentName; WithCacheDir selects
+// another location. Reusing a directory leaves its modification time
+// updated, so age-based cleanup spares trees in use. As no handle outlives
+// the call, a lock taken with WithLock is released before ExtractCached
+// returns; the directory is never cleaned up, so WithImmutable files stay
+// immutable and WithOnCleanup is not called.
+//
+// Example:
+//
+//	dir, err := efs.ExtractCached(assets, "assets", "myapp-assets")
+//	if err != nil { return err }
+//	http.Handle("/", http.FileServer(http.Dir(dir)))
+func ExtractCached(fsys fs.FS, root, name string, opts ...Option) (string, error) {
+	if root == "" {
+		root = "."
+	}
+	o := newOptions(opts)
+	o.contentName = true
+	base, err := o.cacheBase()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(base, 0o700); err != nil {
+		return "", destErr(base, err)
+	}
+	e, err := extract(fsys, []source{{root: root, dest: "."}}, name, base, o)
+	if err != nil {
+		return "", err
+	}
+	if e.unlock != nil {
+		if err := e.unlock(); err != nil {
+			return "", destErr(e.Dir(), err)
+		}
+	}
+	return e.Dir(), nil
+}
+
+// cacheBase returns the WithCacheDir directory or the default cache
+// location, as an absolute path.
+func (o *options) cacheBase() (string, error) {
+	dir := o.cacheDir
+	if dir == "" {
+		userDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", &DestError{Path: "", Err: fmt.Errorf("locate cache dir: %w", err)}
+		}
+		dir = filepath.Join(userDir, "efs")
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir, nil
+	}
+	return abs, nil
+}
+
+// contentDigest returns a digest of the destination paths, types and
+// content of entries, independent of their order.
+func (o *options) contentDigest(fsys fs.FS, entries []planEntry) (string, error) {
+	sorted := slices.SortedFunc(slices.Values(entries), func(a, b planEntry) int { return strings.Compare(a.rel, b.rel) })
+	h := o.digestHash()
+	hh := h.New()
+	for _, e := range sorted {
+		fmt.Fprintf(hh, "%s\x00%v\x00", e.rel, e.d.Type())
+		switch {
+		case e.d.IsDir() || o.isReparse(fsys, e.d):
+		case o.isSymlink(fsys, e.d):
+			target, err := fsys.(SymlinkFS).ReadLink(e.src)
+			if err != nil {
+				return "", sourceErr(e.src, err)
+			}
+			fmt.Fprintf(hh, "%s\x00", target)
+		default:
+			n, _, err := o.copySource(fsys, e.src, hh)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(hh, "\x00%d\x00", n)
+		}
+	}
+	return formatDigest(h, hh.Sum(nil)), nil
+}
+
+// digestKey returns the first 16 hex characters of digest, for names.
+func digestKey(digest string) string {
+	_, key, _ := strings.Cut(digest, ":")
+	return key[:min(len(key), 16)]
+}
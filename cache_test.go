@@ -0,0 +1,85 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExtractCached(t *testing.T) {
+	base := t.TempDir()
+	mem := fstest.MapFS{
+		"assets/a.txt":     {Data: []byte("A")},
+		"assets/sub/b.txt": {Data: []byte("B")},
+	}
+
+	dir, err := ExtractCached(mem, "assets", "app", WithCacheDir(base))
+	if err != nil {
+		t.Fatalf("ExtractCached error: %v", err)
+	}
+	if filepath.Dir(dir) != base || !strings.HasPrefix(filepath.Base(dir), "app-") {
+		t.Fatalf("unexpected cache dir %s", dir)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "sub", "b.txt")); string(data) != "B" {
+		t.Fatalf("unexpected content %q", data)
+	}
+
+	// A second run with the same content reuses the tree without copying.
+	os.WriteFile(filepath.Join(dir, "marker"), nil, 0o644)
+	again, err := ExtractCached(mem, "assets", "app", WithCacheDir(base))
+	if err != nil || again != dir {
+		t.Fatalf("expected reuse of %s, got %s, %v", dir, again, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "marker")); err != nil {
+		t.Errorf("expected the existing tree to be reused: %v", err)
+	}
+
+	// Changed content gets a directory of its own.
+	mem["assets/a.txt"] = &fstest.MapFile{Data: []byte("A2")}
+	changed, err := ExtractCached(mem, "assets", "app", WithCacheDir(base))
+	if err != nil || changed == dir {
+		t.Fatalf("expected a new directory, got %s, %v", changed, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(changed, "a.txt")); string(data) != "A2" {
+		t.Errorf("unexpected content %q", data)
+	}
+
+	// Nothing but the two trees is left in the cache.
+	if names, _ := os.ReadDir(base); len(names) != 2 {
+		t.Errorf("expected 2 cache entries, got %v", names)
+	}
+}
+
+func TestExtractCachedLock(t *testing.T) {
+	base := t.TempDir()
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+
+	cleaned := false
+	dir, err := ExtractCached(mem, ".", "app", WithCacheDir(base), WithLock(),
+		WithOnCleanup(func(CleanupStats) { cleaned = true }))
+	if err != nil {
+		t.Fatalf("ExtractCached error: %v", err)
+	}
+	if IsLocked(dir) {
+		t.Error("expected the lock to be released on return")
+	}
+	if cleaned {
+		t.Error("expected no WithOnCleanup call for a cache directory")
+	}
+
+	// Without its sentinel the tree is extracted again, taking the lock anew.
+	if err := os.Remove(filepath.Join(dir, CompleteFileName)); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ExtractCached(mem, ".", "app", WithCacheDir(base), WithLock())
+	if err != nil || again != dir {
+		t.Fatalf("expected a second locked extraction into %s, got %s, %v", dir, again, err)
+	}
+	release, err := lockDir(dir)
+	if err != nil {
+		t.Fatalf("expected the lock to be free: %v", err)
+	}
+	release()
+}
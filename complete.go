@@ -0,0 +1,115 @@
+package efs
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CompleteFileName is the sentinel written into a stable directory, one
+// created by WithContentName, ExtractCached or ReuseOrExtract, as the last
+// step of a successful extraction. It records the content digest and the
+// efs version, so a directory is reused only if it is complete and current.
+const CompleteFileName = ".efs-complete"
+
+// completion is the content of CompleteFileName.
+type completion struct {
+	Digest  string `json:"digest"`  // Content digest of the tree, see contentDigest
+	Version string `json:"version"` // efs module version that wrote it
+}
+
+// ReuseOrExtract makes dir hold the contents of root in fsys, for programs
+// that keep their assets in a fixed location across runs. If dir carries a
+// CompleteFileName sentinel for the same content digest and efs version, it
+// is reused as is and reused is true. Otherwise, when the sentinel is
+// missing, as after a crash, or stale, as after an upgrade, the tree is
+// extracted next to dir (see WithStageNear) and replaces dir as a whole once
+// complete, sentinel included. opts are the options of Extract; the digest
+// uses SHA-256 unless WithHash selects another algorithm.
+//
+// Example:
+//
+//	reused, err := efs.ReuseOrExtract(assets, "assets", "/var/lib/myapp/assets")
+//	if err != nil { return err }
+//	log.Printf("assets ready (reused: %v)", reused)
+func ReuseOrExtract(fsys fs.FS, root, dir string, opts ...Option) (reused bool, err error) {
+	if root == "" {
+		root = "."
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	o := newOptions(opts)
+	sources := []source{{root: root, dest: "."}}
+	entries, err := prepare(fsys, sources, o)
+	if err != nil {
+		return false, err
+	}
+	digest, err := o.contentDigest(fsys, entries)
+	if err != nil {
+		return false, err
+	}
+	if completed(dir, digest) {
+		touchComplete(dir)
+		return true, nil
+	}
+
+	if o.stageNear == "" {
+		o.stageNear = dir
+	}
+	o.planned = entries // Spare extract a second walk
+	e, err := extract(fsys, sources, ".efs-staging", "", o)
+	if err != nil {
+		return false, err
+	}
+	if err := e.Wait(); err != nil {
+		e.Cleanup()
+		return false, err
+	}
+	if err := o.writeComplete(e.dir, digest); err != nil {
+		e.Cleanup()
+		return false, err
+	}
+	if err := e.MoveTo(dir); err != nil {
+		e.Cleanup()
+		return false, err
+	}
+	return false, nil
+}
+
+// completed reports whether dir carries a sentinel for digest written by
+// this version of efs.
+func completed(dir, digest string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, CompleteFileName))
+	if err != nil {
+		return false
+	}
+	var c completion
+	if err := json.Unmarshal(data, &c); err != nil {
+		return false
+	}
+	return c.Digest == digest && c.Version == moduleVersion()
+}
+
+// touchComplete marks the stable directory dir as used now, so CleanStale
+// measures its age from the last reuse. Errors are ignored.
+func touchComplete(dir string) {
+	now := time.Now()
+	_ = os.Chtimes(filepath.Join(dir, CompleteFileName), now, now)
+	_ = os.Chtimes(dir, now, now)
+}
+
+// writeComplete stores the sentinel for digest in dir.
+func (o *options) writeComplete(dir, digest string) error {
+	data, err := json.Marshal(completion{Digest: digest, Version: moduleVersion()})
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, CompleteFileName)
+	if err := os.WriteFile(path, append(data, '\n'), o.filePerm()); err != nil {
+		return destErr(path, err)
+	}
+	return destErr(path, o.applyOwner(path))
+}
@@ -0,0 +1,87 @@
+package efs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReuseOrExtract(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "assets")
+	mem := fstest.MapFS{
+		"a.txt":   {Data: []byte("A")},
+		"old.txt": {Data: []byte("O")},
+	}
+
+	reused, err := ReuseOrExtract(mem, ".", dir)
+	if err != nil || reused {
+		t.Fatalf("expected a fresh extraction, got %v, %v", reused, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, CompleteFileName)); err != nil {
+		t.Fatalf("expected sentinel: %v", err)
+	}
+	if reused, err := ReuseOrExtract(mem, ".", dir); err != nil || !reused {
+		t.Fatalf("expected reuse, got %v, %v", reused, err)
+	}
+
+	// A missing sentinel, as after a crash, forces a new extraction.
+	os.Remove(filepath.Join(dir, CompleteFileName))
+	if reused, err := ReuseOrExtract(mem, ".", dir); err != nil || reused {
+		t.Fatalf("expected re-extraction without sentinel, got %v, %v", reused, err)
+	}
+
+	// Changed content replaces the tree as a whole.
+	delete(mem, "old.txt")
+	mem["a.txt"] = &fstest.MapFile{Data: []byte("A2")}
+	if reused, err := ReuseOrExtract(mem, ".", dir); err != nil || reused {
+		t.Fatalf("expected re-extraction of stale tree, got %v, %v", reused, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(data) != "A2" {
+		t.Errorf("unexpected content %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected old.txt to be gone, got %v", err)
+	}
+	if names, _ := os.ReadDir(filepath.Dir(dir)); len(names) != 1 {
+		t.Errorf("expected only the target directory, got %v", names)
+	}
+}
+
+func TestReuseOrExtractWalksOnce(t *testing.T) {
+	walks := 0
+	src := lyingFS{
+		MapFS:   fstest.MapFS{"a.txt": {Data: []byte("A")}},
+		readDir: func(entries []fs.DirEntry) []fs.DirEntry { walks++; return entries },
+	}
+	if _, err := ReuseOrExtract(src, ".", filepath.Join(t.TempDir(), "assets")); err != nil {
+		t.Fatalf("ReuseOrExtract error: %v", err)
+	}
+	if walks != 1 {
+		t.Errorf("expected the source to be listed once, got %d", walks)
+	}
+}
+
+func TestContentNameReplacesIncomplete(t *testing.T) {
+	base := t.TempDir()
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+
+	ex, err := Extract(mem, ".", "shared", base, WithContentName())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	os.Remove(ex.Path(CompleteFileName))
+	os.Remove(ex.Path("a.txt"))
+
+	again, err := Extract(mem, ".", "shared", base, WithContentName())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	if again.Dir() != ex.Dir() || again.Report().Files != 1 {
+		t.Fatalf("expected %s to be extracted again, got %s with %+v", ex.Dir(), again.Dir(), again.Report())
+	}
+	if data, err := os.ReadFile(again.Path("a.txt")); err != nil || string(data) != "A" {
+		t.Errorf("unexpected content %q, %v", data, err)
+	}
+}
@@ -0,0 +1,166 @@
+package efs
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// WithConcurrency writes regular files with n workers in parallel, which
+// speeds up trees of many small files. Directories, symlinks and junctions
+// are created first, in order; WithOrdered and WithPriority still decide
+// which files are handed out first, but files may complete in any order.
+// With WithOrdered, WithOnFile calls and audit log records are held back
+// until every earlier file has completed, so they keep the plan order;
+// otherwise they follow completion order. The first failure stops the
+// remaining workers; errors of files already in flight are joined into the
+// returned error. WithOnFile and WithErrorReporter functions are never
+// called concurrently, but run on the worker goroutines. n <= 1 extracts
+// sequentially, which is the default.
+func WithConcurrency(n int) Option {
+	return func(o *options) { o.concurrency = n }
+}
+
+// applyParallel applies entries with WithConcurrency workers: everything but
+// regular files first, in order, then the files in parallel.
+func (a *applier) applyParallel(parent context.Context, entries []planEntry) error {
+	var files []planEntry
+	for _, e := range entries {
+		if e.d.Type().IsRegular() && !a.o.isReparse(a.fsys, e.d) {
+			files = append(files, e)
+			continue
+		}
+		if err := a.step(parent, e); err != nil {
+			return err
+		}
+	}
+
+	if a.o.ordered {
+		a.order = newReorder(files)
+		defer func() { a.order = nil }()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	jobs := make(chan planEntry)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for range min(a.o.concurrency, len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if a.o.lowPriority {
+				defer lowerPriority()()
+			}
+			for e := range jobs {
+				err := a.step(ctx, e)
+				if a.order != nil {
+					if oerr := a.order.done(e.rel); err == nil {
+						err = oerr
+					}
+				}
+				if err == nil || errors.Is(err, context.Canceled) && ctx.Err() != nil {
+					continue // Stopped by another worker's failure or by parent
+				}
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+				cancel()
+			}
+		}()
+	}
+feed:
+	for _, e := range files {
+		select {
+		case jobs <- e:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if a.order != nil {
+		// Files after a gap left by cancellation
+		if err := a.order.flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := parent.Err(); err != nil {
+		return err
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// reorder holds back the per-file callbacks of files completed out of plan
+// order until all earlier files have completed.
+type reorder struct {
+	mu       sync.Mutex
+	order    []string                  // Destination paths of the files, in plan order
+	next     int                       // Index in order of the first file not completed
+	finished map[string]int            // Completed files not released yet
+	pending  map[string][]func() error // Callbacks of the files in finished
+}
+
+func newReorder(files []planEntry) *reorder {
+	r := &reorder{finished: make(map[string]int), pending: make(map[string][]func() error)}
+	for _, e := range files {
+		r.order = append(r.order, e.rel)
+	}
+	return r
+}
+
+// hold queues fn for the file rel.
+func (r *reorder) hold(rel string, fn func() error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending[rel] = append(r.pending[rel], fn)
+}
+
+// done marks the file rel as completed and runs the callbacks that are now
+// in order, returning the first error.
+func (r *reorder) done(rel string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished[rel]++
+	var err error
+	for r.next < len(r.order) && r.finished[r.order[r.next]] > 0 {
+		if rerr := r.release(r.order[r.next]); err == nil {
+			err = rerr
+		}
+		r.next++
+	}
+	return err
+}
+
+// flush runs the remaining callbacks in plan order, skipping files that
+// never completed.
+func (r *reorder) flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var err error
+	for ; r.next < len(r.order); r.next++ {
+		if rerr := r.release(r.order[r.next]); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// release runs the first callback held for rel, if any. r.mu must be held.
+func (r *reorder) release(rel string) error {
+	if r.finished[rel] > 0 {
+		r.finished[rel]--
+	}
+	fns := r.pending[rel]
+	if len(fns) == 0 {
+		return nil
+	}
+	r.pending[rel] = fns[1:]
+	return fns[0]()
+}
@@ -0,0 +1,78 @@
+package efs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithConcurrency(t *testing.T) {
+	mem := fstest.MapFS{}
+	for i := range 200 {
+		mem[fmt.Sprintf("assets/d%d/f%d.txt", i%10, i)] = &fstest.MapFile{Data: []byte(fmt.Sprint(i))}
+	}
+
+	var calls atomic.Int32
+	e, err := Extract(mem, "assets", "par", t.TempDir(), WithConcurrency(8),
+		WithOnFile(func(rel, path string) { calls.Add(1) }))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+	if r := e.Report(); r.Files != 200 || r.Dirs != 10 || calls.Load() != 200 {
+		t.Errorf("unexpected report %+v after %d WithOnFile calls", r, calls.Load())
+	}
+	if err := e.Verify(); err != nil {
+		t.Errorf("Verify error: %v", err)
+	}
+
+	// One failing file stops the extraction and leaves nothing behind.
+	base := t.TempDir()
+	bad := badFS{base: mem, fail: "assets/d3/f123.txt"}
+	if _, err := Extract(bad, "assets", "par", base, WithConcurrency(8)); err == nil {
+		t.Fatal("expected an error")
+	} else if se := (*SourceError)(nil); !errors.As(err, &se) || se.Path != "assets/d3/f123.txt" {
+		t.Errorf("expected a *SourceError for the failing file, got %v", err)
+	}
+	if entries, _ := os.ReadDir(base); len(entries) != 0 {
+		t.Errorf("expected no leftovers, got %v", entries)
+	}
+}
+
+func TestWithConcurrencyOrdered(t *testing.T) {
+	mem := fstest.MapFS{}
+	for i := range 200 {
+		// Early files are the largest, so workers tend to finish them last.
+		mem[fmt.Sprintf("d%d/f%03d.txt", i%7, i)] = &fstest.MapFile{Data: bytes.Repeat([]byte("x"), (200-i)*512)}
+	}
+
+	var files []string
+	var audit bytes.Buffer
+	e, err := Extract(mem, ".", "par", t.TempDir(), WithConcurrency(8), WithOrdered(), WithAuditLog(&audit),
+		WithOnFile(func(rel, path string) { files = append(files, rel) }))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+	if len(files) != 200 || !slices.IsSorted(files) {
+		t.Errorf("expected 200 WithOnFile calls in lexical order, got %v", files)
+	}
+	var sources []string
+	for line := range strings.Lines(audit.String()) {
+		var rec auditRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("bad audit line %q: %v", line, err)
+		}
+		sources = append(sources, rec.Source)
+	}
+	if !slices.Equal(sources, files) {
+		t.Errorf("expected audit records in WithOnFile order, got %v", sources)
+	}
+}
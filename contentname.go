@@ -0,0 +1,66 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// WithContentName names the temporary directory of Extract and
+// ExtractToTemp "<tempPrefix>-<key>", where key is derived from a hash of
+// the paths and content to extract (see ExtractCached), instead of adding a
+// random suffix. Processes embedding the same assets thus converge on one
+// directory, and its path is predictable for debugging.
+//
+// If the directory already exists with a CompleteFileName sentinel for the
+// same content and efs version, it is reused as is and nothing is written.
+// Otherwise the tree is extracted into a hidden sibling and, with the
+// sentinel written last, renamed into place, replacing an incomplete or
+// stale directory; if another process wins that race, its copy is used. As
+// the directory is shared, Cleanup leaves it in place and only releases
+// what the handle holds. WithPriority has no effect, WithLock covers only a
+// directory this process created, and WithIDInName defeats the sharing.
+// ExtractFile ignores the option.
+func WithContentName() Option {
+	return func(o *options) { o.contentName = true }
+}
+
+// reuseExtraction returns a handle to the existing content-named directory
+// dir, marking it as used so CleanStale spares it.
+func reuseExtraction(fsys fs.FS, sources []source, entries []planEntry, dir string, o *options, start time.Time) *Extraction {
+	touchComplete(dir)
+	e := &Extraction{dir: dir, fsys: fsys, sources: sources, entries: entries, o: o, created: start, shared: true}
+	e.report.Duration = time.Since(start)
+	if o.reportTo != nil {
+		*o.reportTo = e.report
+	}
+	return e
+}
+
+// publish moves the completed staging directory of a WithContentName
+// extraction with the given digest to final, replacing an incomplete or
+// stale tree there. If another process published the same content first,
+// the staging copy is discarded in favor of theirs.
+func (e *Extraction) publish(final, digest string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !completed(final, digest) {
+		err := commitStaging(e.dir, final)
+		if err != nil && !completed(final, digest) {
+			return destErr(final, err)
+		}
+		if err == nil {
+			trackDone(e.dir)
+			e.dir, e.shared = final, true
+			return nil
+		}
+	}
+	// Another process published the same content first
+	if err := errors.Join(e.releaseLocked(), os.RemoveAll(e.dir)); err != nil {
+		return destErr(e.dir, err)
+	}
+	trackDone(e.dir)
+	e.dir, e.shared = final, true
+	return nil
+}
@@ -0,0 +1,49 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithContentName(t *testing.T) {
+	base := t.TempDir()
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+
+	first, err := Extract(mem, ".", "shared", base, WithContentName())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	second, err := Extract(mem, ".", "shared", base, WithContentName())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	if first.Dir() != second.Dir() || filepath.Dir(first.Dir()) != base {
+		t.Fatalf("expected one shared directory in %s, got %s and %s", base, first.Dir(), second.Dir())
+	}
+	if r := second.Report(); r.Files != 0 {
+		t.Errorf("expected the second extraction to reuse the tree, wrote %d files", r.Files)
+	}
+
+	// Cleanup leaves the shared directory for the other users.
+	if err := first.Cleanup(); err != nil {
+		t.Fatalf("Cleanup error: %v", err)
+	}
+	if data, err := os.ReadFile(second.Path("a.txt")); err != nil || string(data) != "A" {
+		t.Fatalf("expected shared tree to survive Cleanup, got %q, %v", data, err)
+	}
+
+	other, err := Extract(fstest.MapFS{"a.txt": {Data: []byte("B")}}, ".", "shared", base, WithContentName())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	if other.Dir() == first.Dir() {
+		t.Errorf("expected different content to get its own directory")
+	}
+
+	// No staging directories are left behind.
+	if names, _ := os.ReadDir(base); len(names) != 2 {
+		t.Errorf("expected 2 directories, got %v", names)
+	}
+}
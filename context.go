@@ -0,0 +1,43 @@
+package efs
+
+import (
+	"context"
+	"io/fs"
+)
+
+// WithContext lets ctx abort an extraction: Extract, ExtractToTemp and
+// ExtractFile check it while walking the source and between entries, and
+// when it is done they remove the partial temporary directory or file and
+// return ctx.Err(). Entries left for the background by WithPriority are not
+// affected once Extract has returned; stop them with Cleanup. Plan.Apply
+// takes its context as an argument instead.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// context returns the WithContext context, or context.Background().
+func (o *options) context() context.Context {
+	if o.ctx == nil {
+		return context.Background()
+	}
+	return o.ctx
+}
+
+// ExtractToTempContext is ExtractToTemp aborted when ctx is done; see
+// WithContext. On cancellation the partial temporary directory is removed
+// and ctx.Err() is returned.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+//	defer cancel()
+//	dir, cleanup, err := ExtractToTempContext(ctx, assets, "assets", "myassets", "")
+func ExtractToTempContext(ctx context.Context, fsys fs.FS, root string, tempPrefix string, tempDir string, opts ...Option) (string, func(), error) {
+	return ExtractToTemp(fsys, root, tempPrefix, tempDir, append(opts, WithContext(ctx))...)
+}
+
+// ExtractFileContext is ExtractFile aborted when ctx is done; see
+// WithContext.
+func ExtractFileContext(ctx context.Context, fsys fs.FS, filePath string, tempPrefix string, tempDir string, opts ...Option) (string, func(), error) {
+	return ExtractFile(fsys, filePath, tempPrefix, tempDir, append(opts, WithContext(ctx))...)
+}
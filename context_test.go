@@ -0,0 +1,62 @@
+package efs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+// cancelFS cancels a context when the file name is opened.
+type cancelFS struct {
+	fs.FS
+	name   string
+	cancel context.CancelFunc
+}
+
+func (c cancelFS) Open(name string) (fs.File, error) {
+	if name == c.name {
+		c.cancel()
+	}
+	return c.FS.Open(name)
+}
+
+func TestExtractContextCanceled(t *testing.T) {
+	base := t.TempDir()
+	mem := fstest.MapFS{
+		"assets/a.txt":   {Data: []byte("A")},
+		"assets/b/c.txt": {Data: []byte("C")},
+		"assets/d.txt":   {Data: []byte("D")},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := ExtractToTempContext(ctx, mem, "assets", "ctx", base); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled before the walk, got %v", err)
+	}
+	if _, _, err := ExtractFileContext(ctx, mem, "assets/a.txt", "ctx", base); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled for ExtractFile, got %v", err)
+	}
+
+	// Canceled while extracting: the partial directory must be removed.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	src := cancelFS{FS: mem, name: "assets/b/c.txt", cancel: cancel}
+	if _, err := Extract(src, "assets", "ctx", base, WithContext(ctx), WithOrdered()); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled mid-extraction, got %v", err)
+	}
+	if entries, _ := os.ReadDir(base); len(entries) != 0 {
+		t.Errorf("expected no leftovers, got %v", entries)
+	}
+
+	dir, cleanup, err := ExtractToTempContext(context.Background(), mem, "assets", "ctx", base)
+	if err != nil {
+		t.Fatalf("ExtractToTempContext error: %v", err)
+	}
+	defer cleanup()
+	if _, err := os.Stat(dir + "/d.txt"); err != nil {
+		t.Errorf("expected a complete extraction: %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package efs
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrChecksumMismatch is reported (wrapped in a *SourceError) when a dataset
+// does not match its .sha256 sidecar.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ErrUnsupportedCompression is reported by ExtractDataset for compression
+// formats it has no decoder for. gzip is built in; others, such as zstd,
+// are decoded once WithDecompressor supplies a decoder.
+var ErrUnsupportedCompression = errors.New("unsupported compression format")
+
+// WithDecompressor makes ExtractDataset decode files whose name ends in ext
+// (e.g. ".zst", matched case-insensitively) with newReader, which wraps the
+// compressed stream. It keeps efs free of third-party dependencies while
+// letting callers plug in a decoder, e.g. for zstd with
+// github.com/klauspost/compress/zstd:
+//
+//	efs.WithDecompressor(".zst", func(r io.Reader) (io.ReadCloser, error) {
+//		d, err := zstd.NewReader(r)
+//		if err != nil { return nil, err }
+//		return d.IOReadCloser(), nil
+//	})
+//
+// A decoder for ".gz" replaces the built-in gzip one.
+func WithDecompressor(ext string, newReader func(r io.Reader) (io.ReadCloser, error)) Option {
+	return func(o *options) {
+		if o.decompressors == nil {
+			o.decompressors = make(map[string]func(io.Reader) (io.ReadCloser, error))
+		}
+		o.decompressors[strings.ToLower(ext)] = newReader
+	}
+}
+
+// compressedExts are extensions of compressed formats without a built-in
+// decoder, rejected unless WithDecompressor handles them.
+var compressedExts = []string{".zst", ".zstd", ".xz", ".bz2", ".lz4"}
+
+// decompressor returns the decoder for files ending in ext, if any.
+func (o *options) decompressor(ext string) func(io.Reader) (io.ReadCloser, error) {
+	if fn := o.decompressors[ext]; fn != nil {
+		return fn
+	}
+	if ext == ".gz" {
+		return func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }
+	}
+	return nil
+}
+
+// ExtractDataset streams the large file name from fsys into destDir without
+// holding it in memory, decompressing it on the way if it ends in ".gz" or
+// an extension registered with WithDecompressor, and returns the path of the
+// written file (name's base name without that extension). zstd is not built
+// in: ".zst" files need a decoder from WithDecompressor and otherwise fail
+// with ErrUnsupportedCompression. destDir is created if needed. If fsys
+// contains a sidecar name+".sha256" in sha256sum format, the file as stored,
+// including any data the decoder leaves unread, is verified against it and a
+// mismatch fails with ErrChecksumMismatch. progress, if not nil, is called
+// as the source is read with the bytes read so far and the source size. The
+// file and directory permission options (WithFileMode, WithDirMode,
+// WithExactPerms, WithStrictPerms) and WithOwner apply to the output.
+//
+// The output is written to a temporary file in destDir and renamed into
+// place only once it is complete and verified, so an existing dataset is
+// never replaced by a partial or corrupt one.
+func ExtractDataset(fsys fs.FS, name, destDir string, progress func(read, total int64), opts ...Option) (string, error) {
+	o := newOptions(opts)
+	base := path.Base(name)
+	ext := strings.ToLower(path.Ext(base))
+	decode := o.decompressor(ext)
+	if decode != nil {
+		base = strings.TrimSuffix(base, path.Ext(base))
+	} else if slices.Contains(compressedExts, ext) {
+		return "", &SourceError{Path: name, Err: fmt.Errorf("%w: %s (see WithDecompressor)", ErrUnsupportedCompression, ext)}
+	}
+
+	want, err := sidecarDigest(fsys, name)
+	if err != nil {
+		return "", sourceErr(name+".sha256", err)
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", sourceErr(name, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", sourceErr(name, err)
+	}
+
+	if err := os.MkdirAll(destDir, o.dirPerm()); err != nil {
+		return "", destErr(destDir, err)
+	}
+	tmp, err := createUnique(destDir, "."+base+".efs-", o.filePerm())
+	if err != nil {
+		return "", destErr(destDir, err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	h := sha256.New()
+	src := &progressReader{r: io.TeeReader(f, h), total: info.Size(), fn: progress}
+	err = copyDataset(tmp, src, name, decode)
+	if err == nil {
+		err = destErr(tmp.Name(), tmp.Sync())
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = destErr(tmp.Name(), closeErr)
+	}
+	if err != nil {
+		return "", err
+	}
+	if want != "" && hex.EncodeToString(h.Sum(nil)) != want {
+		return "", &SourceError{Path: name, Err: ErrChecksumMismatch}
+	}
+	if err := o.applyPerm(tmp.Name(), o.filePerm()); err != nil {
+		return "", destErr(tmp.Name(), err)
+	}
+	if err := o.applyOwner(tmp.Name()); err != nil {
+		return "", destErr(tmp.Name(), err)
+	}
+
+	dst := filepath.Join(destDir, base)
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return "", destErr(dst, err)
+	}
+	return dst, nil
+}
+
+// createUnique creates a new file in dir whose name starts with prefix
+// followed by a random suffix, with perm subject to the umask, unlike
+// os.CreateTemp.
+func createUnique(dir, prefix string, perm fs.FileMode) (*os.File, error) {
+	for try := 0; ; try++ {
+		f, err := os.OpenFile(uniqueName(dir, prefix), os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+		if err == nil || !errors.Is(err, fs.ErrExist) || try >= 10000 {
+			return f, err
+		}
+	}
+}
+
+// copyDataset copies src, the content of the source file name, to dst,
+// decoding it with decode if that is not nil.
+func copyDataset(dst *os.File, src io.Reader, name string, decode func(io.Reader) (io.ReadCloser, error)) error {
+	r := src
+	if decode != nil {
+		zr, err := decode(src)
+		if err != nil {
+			return &SourceError{Path: name, Err: err}
+		}
+		defer zr.Close()
+		r = zr
+	}
+	buf := copyBufs.Get().(*[]byte)
+	defer copyBufs.Put(buf)
+	for {
+		n, err := r.Read(*buf)
+		if n > 0 {
+			if _, err := dst.Write((*buf)[:n]); err != nil {
+				return &DestError{Path: dst.Name(), Err: err}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return &SourceError{Path: name, Err: err}
+		}
+	}
+	// Read what the decoder left, such as trailing padding, so that the
+	// checksum covers the whole file.
+	if _, err := io.Copy(io.Discard, src); err != nil {
+		return &SourceError{Path: name, Err: err}
+	}
+	return nil
+}
+
+// sidecarDigest returns the hex SHA-256 digest from name+".sha256", or ""
+// if there is no sidecar.
+func sidecarDigest(fsys fs.FS, name string) (string, error) {
+	data, err := fs.ReadFile(fsys, name+".sha256")
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return "", fmt.Errorf("malformed checksum file")
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// progressReader reports how much of its source has been read.
+type progressReader struct {
+	r     io.Reader
+	read  int64
+	total int64
+	fn    func(read, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	if n > 0 && p.fn != nil {
+		p.fn(p.read, p.total)
+	}
+	return n, err
+}
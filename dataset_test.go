@@ -0,0 +1,109 @@
+package efs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExtractDataset(t *testing.T) {
+	raw := bytes.Repeat([]byte("weights"), 100000)
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(raw)
+	zw.Close()
+	sum := sha256.Sum256(gz.Bytes())
+
+	mem := fstest.MapFS{
+		"models/m.bin.gz":        {Data: gz.Bytes()},
+		"models/m.bin.gz.sha256": {Data: []byte(hex.EncodeToString(sum[:]) + "  m.bin.gz\n")},
+		"models/bad.bin":         {Data: []byte("corrupt")},
+		"models/bad.bin.sha256":  {Data: []byte(hex.EncodeToString(sum[:]) + "\n")},
+		"models/m.zst":           {Data: []byte("zstd")},
+	}
+	dir := filepath.Join(t.TempDir(), "data")
+
+	var last, total int64
+	got, err := ExtractDataset(mem, "models/m.bin.gz", dir, func(read, size int64) { last, total = read, size })
+	if err != nil {
+		t.Fatalf("ExtractDataset error: %v", err)
+	}
+	if got != filepath.Join(dir, "m.bin") {
+		t.Errorf("unexpected output path %s", got)
+	}
+	if data, _ := os.ReadFile(got); !bytes.Equal(data, raw) {
+		t.Errorf("expected decompressed content, got %d bytes", len(data))
+	}
+	if last != int64(gz.Len()) || total != int64(gz.Len()) {
+		t.Errorf("expected final progress %d/%d, got %d/%d", gz.Len(), gz.Len(), last, total)
+	}
+
+	if _, err := ExtractDataset(mem, "models/bad.bin", dir, nil); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected ErrChecksumMismatch, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bad.bin")); !os.IsNotExist(err) {
+		t.Errorf("expected no output for a corrupt dataset, got %v", err)
+	}
+	if _, err := ExtractDataset(mem, "models/m.zst", dir, nil); !errors.Is(err, ErrUnsupportedCompression) {
+		t.Errorf("expected ErrUnsupportedCompression, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected only m.bin in %s, got %d entries", dir, len(entries))
+	}
+}
+
+func TestExtractDatasetDecompressor(t *testing.T) {
+	raw := []byte("weights")
+	mem := fstest.MapFS{"models/m.bin.zst": {Data: []byte(hex.EncodeToString(raw))}}
+	dir := t.TempDir()
+
+	// A stand-in for a zstd decoder such as klauspost/compress/zstd.
+	decoder := WithDecompressor(".ZST", func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(hex.NewDecoder(r)), nil
+	})
+	got, err := ExtractDataset(mem, "models/m.bin.zst", dir, nil, decoder, WithFileMode(0o640), WithExactPerms())
+	if err != nil {
+		t.Fatalf("ExtractDataset error: %v", err)
+	}
+	if got != filepath.Join(dir, "m.bin") {
+		t.Errorf("unexpected output path %s", got)
+	}
+	if data, _ := os.ReadFile(got); !bytes.Equal(data, raw) {
+		t.Errorf("expected decoded content, got %q", data)
+	}
+	if info, err := os.Stat(got); err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0o640 {
+		t.Errorf("expected mode 0640, got %v", info.Mode().Perm())
+	}
+}
+
+func TestExtractDatasetTrailingData(t *testing.T) {
+	// The decoder stops at the end of its frame, before the padding.
+	stored := []byte("weights\x00\x00\x00\x00")
+	sum := sha256.Sum256(stored)
+	mem := fstest.MapFS{
+		"m.bin.zst":        {Data: stored},
+		"m.bin.zst.sha256": {Data: []byte(hex.EncodeToString(sum[:]) + "\n")},
+	}
+	decoder := WithDecompressor(".zst", func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(io.LimitReader(r, 7)), nil
+	})
+	var last int64
+	got, err := ExtractDataset(mem, "m.bin.zst", t.TempDir(), func(read, total int64) { last = read }, decoder)
+	if err != nil {
+		t.Fatalf("ExtractDataset error: %v", err)
+	}
+	if data, _ := os.ReadFile(got); string(data) != "weights" {
+		t.Errorf("unexpected content %q", data)
+	}
+	if last != int64(len(stored)) {
+		t.Errorf("expected progress to cover the whole file, got %d", last)
+	}
+}
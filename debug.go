@@ -0,0 +1,74 @@
+package efs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// debugEnv is the environment variable that enables tracing at startup.
+const debugEnv = "EFS_DEBUG"
+
+// tracer holds the destination of trace output; on mirrors w != nil so that
+// disabled tracing costs a single atomic load per operation.
+var tracer struct {
+	on atomic.Bool
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func init() {
+	if on, _ := strconv.ParseBool(os.Getenv(debugEnv)); on {
+		SetDebugWriter(os.Stderr)
+	}
+}
+
+// SetDebugWriter sends a trace of every file system operation of every
+// extraction (walk entry, mkdir, write, chmod, cleanup) with its duration and
+// any error to w, one line per operation, so slow or failing extractions can
+// be diagnosed in the field. A nil w turns tracing off.
+//
+// Tracing can also be enabled without code changes by starting the program
+// with EFS_DEBUG=1 (or any other true value accepted by strconv.ParseBool),
+// which traces to standard error. Under WithRedaction, traced paths and
+// errors are redacted like error messages.
+func SetDebugWriter(w io.Writer) {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	tracer.w = w
+	tracer.on.Store(w != nil)
+}
+
+// tracing reports whether operations of this extraction are traced.
+func (o *options) tracing() bool {
+	return tracer.on.Load()
+}
+
+// traceOp runs fn and traces it as op on path if tracing is enabled.
+func (o *options) traceOp(op, path string, fn func() error) error {
+	if !o.tracing() {
+		return fn()
+	}
+	start := time.Now()
+	err := fn()
+	tracef(op, o.display(path), time.Since(start), o.redactErr(err, path))
+	return err
+}
+
+// tracef writes one trace line.
+func tracef(op, path string, d time.Duration, err error) {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+	if tracer.w == nil {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(tracer.w, "efs: %-7s %s %v error: %v\n", op, path, d, err)
+		return
+	}
+	fmt.Fprintf(tracer.w, "efs: %-7s %s %v\n", op, path, d)
+}
@@ -0,0 +1,53 @@
+package efs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSetDebugWriter(t *testing.T) {
+	var buf bytes.Buffer
+	SetDebugWriter(&buf)
+	defer SetDebugWriter(nil)
+
+	mem := fstest.MapFS{"assets/sub/a.txt": {Data: []byte("A")}}
+	ex, err := Extract(mem, "assets", "debug", t.TempDir(), WithExactPerms())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	ex.Cleanup()
+
+	out := buf.String()
+	for _, op := range []string{"walk", "mkdir", "write", "chmod", "extract", "cleanup"} {
+		if !strings.Contains(out, "efs: "+op+" ") {
+			t.Errorf("trace lacks %s:\n%s", op, out)
+		}
+	}
+	if !strings.Contains(out, "assets/sub/a.txt") {
+		t.Errorf("trace lacks the walked entry:\n%s", out)
+	}
+
+	// Redacted extractions are traced with redacted names.
+	buf.Reset()
+	if _, cleanup, err := ExtractToTemp(mem, "assets", "debug", t.TempDir(), WithRedaction(HashName)); err == nil {
+		cleanup()
+	}
+	out = buf.String()
+	if strings.Contains(out, "a.txt") || strings.Contains(out, "sub") {
+		t.Errorf("expected no traced names under WithRedaction, got:\n%s", out)
+	}
+	if !strings.Contains(out, "efs: write ") || !strings.Contains(out, HashName("assets/sub/a.txt")) {
+		t.Errorf("expected a redacted trace under WithRedaction, got:\n%s", out)
+	}
+
+	SetDebugWriter(nil)
+	buf.Reset()
+	if _, cleanup, err := ExtractToTemp(mem, "assets", "debug", t.TempDir()); err == nil {
+		cleanup()
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no trace once disabled, got:\n%s", buf.String())
+	}
+}
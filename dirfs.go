@@ -0,0 +1,64 @@
+package efs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// dirFS is an os.DirFS that remembers its root directory, which lets efs offer
+// on-disk extras (such as extended attributes) that a plain fs.FS cannot expose.
+type dirFS string
+
+// DirFS returns a file system for the tree of files rooted at dir. It behaves
+// like os.DirFS but additionally implements XattrFS and SymlinkFS, so extended
+// attributes and symlinks can be preserved with WithXattrs and WithSymlinks.
+func DirFS(dir string) fs.FS {
+	return dirFS(dir)
+}
+
+func (d dirFS) Open(name string) (fs.File, error) {
+	return os.DirFS(string(d)).Open(name)
+}
+
+func (d dirFS) ReadFile(name string) ([]byte, error) {
+	return os.DirFS(string(d)).(fs.ReadFileFS).ReadFile(name)
+}
+
+func (d dirFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.DirFS(string(d)).(fs.ReadDirFS).ReadDir(name)
+}
+
+func (d dirFS) Stat(name string) (fs.FileInfo, error) {
+	return os.DirFS(string(d)).(fs.StatFS).Stat(name)
+}
+
+// Xattrs implements XattrFS.
+func (d dirFS) Xattrs(name string) (map[string][]byte, error) {
+	full, err := d.join("xattrs", name)
+	if err != nil {
+		return nil, err
+	}
+	return readXattrs(full)
+}
+
+// ReadLink implements SymlinkFS.
+func (d dirFS) ReadLink(name string) (string, error) {
+	full, err := d.join("readlink", name)
+	if err != nil {
+		return "", err
+	}
+	target, err := os.Readlink(full)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(target), nil
+}
+
+// join converts a slash-separated fs.FS name into a native path below d.
+func (d dirFS) join(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(string(d), filepath.FromSlash(name)), nil
+}
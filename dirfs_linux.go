@@ -0,0 +1,19 @@
+package efs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// openSource opens path for reading, with O_NOATIME if requested. The kernel
+// only allows O_NOATIME for the file owner, so EPERM falls back to a plain open.
+func openSource(path string, noAtime bool) (*os.File, error) {
+	if noAtime {
+		f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NOATIME, 0)
+		if !errors.Is(err, syscall.EPERM) {
+			return f, err
+		}
+	}
+	return os.Open(path)
+}
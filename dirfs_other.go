@@ -0,0 +1,10 @@
+//go:build !linux
+
+package efs
+
+import "os"
+
+// openSource opens path for reading; O_NOATIME is not available here.
+func openSource(path string, noAtime bool) (*os.File, error) {
+	return os.Open(path)
+}
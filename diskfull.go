@@ -0,0 +1,39 @@
+package efs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DiskFullError is reported (wrapped in a *DestError) when the destination
+// file system runs out of space during extraction. It tells operators whether
+// the volume needs to grow or the extraction should go elsewhere.
+type DiskFullError struct {
+	Written   int64 // Bytes extracted before the failure
+	Remaining int64 // Bytes still to be extracted, including the failed file
+	Free      int64 // Free bytes on the destination at failure time; -1 if unknown
+	Err       error
+}
+
+func (e *DiskFullError) Error() string {
+	free := "unknown"
+	if e.Free >= 0 {
+		free = fmt.Sprintf("%d bytes", e.Free)
+	}
+	return fmt.Sprintf("disk full (%d bytes written, %d remaining, %s free): %v", e.Written, e.Remaining, free, e.Err)
+}
+
+func (e *DiskFullError) Unwrap() error { return e.Err }
+
+// withDiskFull enriches a *DestError caused by a full disk with a
+// *DiskFullError; dir is the destination being written. Other errors are
+// returned unchanged.
+func withDiskFull(err error, dir string, written, remaining int64) error {
+	var de *DestError
+	var dfe *DiskFullError
+	if !isDiskFull(err) || !errors.As(err, &de) || errors.As(err, &dfe) {
+		return err
+	}
+	de.Err = &DiskFullError{Written: written, Remaining: remaining, Free: freeSpace(dir), Err: de.Err}
+	return err
+}
@@ -0,0 +1,41 @@
+//go:build unix
+
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestWithDiskFull(t *testing.T) {
+	dir := t.TempDir()
+	err := destErr(dir, &fs.PathError{Op: "write", Path: dir, Err: syscall.ENOSPC})
+
+	err = withDiskFull(err, dir, 100, 50)
+	var dfe *DiskFullError
+	if !errors.As(err, &dfe) {
+		t.Fatalf("expected *DiskFullError, got %v", err)
+	}
+	var de *DestError
+	if !errors.As(err, &de) || !errors.Is(err, syscall.ENOSPC) {
+		t.Errorf("expected *DestError chain down to ENOSPC, got %v", err)
+	}
+	if dfe.Written != 100 || dfe.Remaining != 50 {
+		t.Errorf("unexpected byte counts: %+v", dfe)
+	}
+	if runtime.GOOS == "linux" && dfe.Free < 0 {
+		t.Errorf("expected free space on linux, got %d", dfe.Free)
+	}
+	if !strings.Contains(err.Error(), "100 bytes written, 50 remaining") {
+		t.Errorf("unexpected message: %v", err)
+	}
+
+	other := destErr(dir, syscall.EACCES)
+	if got := withDiskFull(other, dir, 1, 1); got.Error() != other.Error() {
+		t.Errorf("expected non-ENOSPC error unchanged, got %v", got)
+	}
+}
@@ -0,0 +1,111 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// OpKind is the kind of an Operation reported by Plan.DryRun.
+type OpKind int
+
+const (
+	OpMkdir     OpKind = iota // Create a directory
+	OpWrite                   // Write a new file
+	OpOverwrite               // Replace an existing file
+	OpSymlink                 // Create or replace a symlink or junction
+	OpDelete                  // Remove an entry (with everything below it) under WithMirror or WithAtomic
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case OpMkdir:
+		return "mkdir"
+	case OpWrite:
+		return "write"
+	case OpOverwrite:
+		return "overwrite"
+	case OpSymlink:
+		return "symlink"
+	case OpDelete:
+		return "delete"
+	}
+	return "unknown"
+}
+
+// Operation is a single change Plan.Apply would make to the destination.
+type Operation struct {
+	Kind OpKind
+	Path string // Slash-separated, relative to the destination; "." for the destination itself
+	Size int64  // Bytes to write for OpWrite and OpOverwrite
+}
+
+// DryRun returns the operations Apply would perform on dst, in order,
+// without touching the disk, so deploy tooling can show a plan before
+// extracting into shared directories. Files the conflict policy would leave
+// alone (see WithConflict) are not listed, and with ConflictError DryRun
+// fails like Apply would. Under WithAtomic, which replaces dst as a whole,
+// and under WithMirror, the entries that would disappear are listed as
+// OpDelete at the end. Files efs writes for its own bookkeeping, such as
+// the WithMetaFile marker, are not listed. The result reflects dst at the
+// time of the call.
+func (p *Plan) DryRun(dst string) ([]Operation, error) {
+	o := p.o
+	absDst, err := filepath.Abs(dst)
+	if err != nil {
+		absDst = dst
+	}
+
+	var ops []Operation
+	if _, err := os.Lstat(absDst); errors.Is(err, fs.ErrNotExist) {
+		ops = append(ops, Operation{Kind: OpMkdir, Path: "."})
+	} else if err != nil {
+		return nil, destErr(absDst, err)
+	}
+	for _, e := range p.entries {
+		target := filepath.Join(absDst, filepath.FromSlash(e.rel))
+		_, err := os.Lstat(target)
+		exists := err == nil
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, destErr(target, err)
+		}
+
+		switch {
+		case o.isReparse(p.fsys, e.d) || o.isSymlink(p.fsys, e.d):
+			ops = append(ops, Operation{Kind: OpSymlink, Path: e.rel})
+		case e.d.IsDir():
+			if !exists {
+				ops = append(ops, Operation{Kind: OpMkdir, Path: e.rel})
+			}
+		case !exists:
+			ops = append(ops, Operation{Kind: OpWrite, Path: e.rel, Size: e.size})
+		case o.atomic:
+			ops = append(ops, Operation{Kind: OpOverwrite, Path: e.rel, Size: e.size})
+		default:
+			skip, err := o.resolveConflict(target)
+			if err != nil {
+				return nil, destErr(target, err)
+			}
+			if !skip && o.conflict == ConflictOverwriteIfDifferent {
+				if skip, err = o.unchanged(p.fsys, e.src, target); err != nil {
+					return nil, err
+				}
+			}
+			if !skip {
+				ops = append(ops, Operation{Kind: OpOverwrite, Path: e.rel, Size: e.size})
+			}
+		}
+	}
+
+	if o.mirror || o.atomic {
+		extra, err := o.extras(absDst, p.entries)
+		if err != nil {
+			return nil, destErr(absDst, err)
+		}
+		for _, rel := range extra {
+			ops = append(ops, Operation{Kind: OpDelete, Path: rel})
+		}
+	}
+	return ops, nil
+}
@@ -0,0 +1,70 @@
+package efs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDryRun(t *testing.T) {
+	mem := fstest.MapFS{
+		"cfg/app.yaml":   {Data: []byte("default")},
+		"cfg/same.yaml":  {Data: []byte("same")},
+		"cfg/sub/x.yaml": {Data: []byte("x")},
+	}
+	dst := t.TempDir()
+	for name, data := range map[string]string{"app.yaml": "user", "same.yaml": "same", "stale.yaml": "old"} {
+		if err := os.WriteFile(filepath.Join(dst, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	format := func(ops []Operation) []string {
+		var out []string
+		for _, op := range ops {
+			out = append(out, fmt.Sprintf("%s %s %d", op.Kind, op.Path, op.Size))
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		dst  string
+		opts []Option
+		want []string
+	}{
+		{"fresh", filepath.Join(dst, "new"), nil, []string{"mkdir . 0", "write app.yaml 7", "write same.yaml 4", "mkdir sub 0", "write sub/x.yaml 1"}},
+		{"overwrite", dst, nil, []string{"overwrite app.yaml 7", "overwrite same.yaml 4", "mkdir sub 0", "write sub/x.yaml 1"}},
+		{"skip existing", dst, []Option{WithConflict(ConflictSkipExisting)}, []string{"mkdir sub 0", "write sub/x.yaml 1"}},
+		{"if different mirror", dst, []Option{WithConflict(ConflictOverwriteIfDifferent), WithMirror()}, []string{"overwrite app.yaml 7", "mkdir sub 0", "write sub/x.yaml 1", "delete stale.yaml 0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Prepare(mem, "cfg", append(tt.opts, WithOrdered())...)
+			if err != nil {
+				t.Fatalf("Prepare error: %v", err)
+			}
+			ops, err := p.DryRun(tt.dst)
+			if err != nil {
+				t.Fatalf("DryRun error: %v", err)
+			}
+			if got := format(ops); !slices.Equal(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	p, err := Prepare(mem, "cfg", WithConflict(ConflictError))
+	if err != nil {
+		t.Fatalf("Prepare error: %v", err)
+	}
+	if _, err := p.DryRun(dst); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "sub")); !os.IsNotExist(err) {
+		t.Errorf("expected DryRun to leave the disk alone, got %v", err)
+	}
+}
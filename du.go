@@ -0,0 +1,49 @@
+package efs
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// fileID identifies a file across hard links.
+type fileID struct {
+	dev, ino uint64
+}
+
+// DiskUsage walks dir and returns the space its entries actually occupy on
+// disk and the number of distinct inodes (files, directories and symlinks,
+// including dir itself). Unlike the logical sizes in Report.Bytes, bytes
+// counts allocated blocks, so sparse files count only their written parts,
+// and a file with several hard links inside dir is counted once. Symlinks
+// are not followed. On Windows, bytes is the allocated size of compressed
+// and sparse files and the logical size of other files; on platforms without
+// block accounting, such as js/wasm, it is the logical size.
+func DiskUsage(dir string) (bytes int64, inodes int64, err error) {
+	seen := make(map[fileID]bool)
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		id, size, linked, err := entryUsage(p, info)
+		if err != nil {
+			return err
+		}
+		if linked {
+			if seen[id] {
+				return nil
+			}
+			seen[id] = true
+		}
+		bytes += size
+		inodes++
+		return nil
+	})
+	if err != nil {
+		return 0, 0, &DestError{Path: dir, Err: err}
+	}
+	return bytes, inodes, nil
+}
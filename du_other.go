@@ -0,0 +1,14 @@
+//go:build !unix && !windows
+
+package efs
+
+import "io/fs"
+
+// entryUsage returns the logical size of the entry; block accounting and
+// hard link detection are not available on this platform.
+func entryUsage(path string, info fs.FileInfo) (id fileID, size int64, linked bool, err error) {
+	if info.IsDir() {
+		return id, 0, false, nil
+	}
+	return id, info.Size(), false, nil
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package efs
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// entryUsage returns the identity and allocated size of the entry at path.
+// linked reports whether the entry has further hard links that must not be
+// counted twice.
+func entryUsage(path string, info fs.FileInfo) (id fileID, size int64, linked bool, err error) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return id, info.Size(), false, nil
+	}
+	id = fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)}
+	// st_blocks is in 512-byte units on every Unix, regardless of st_blksize.
+	return id, int64(st.Blocks) * 512, !info.IsDir() && uint64(st.Nlink) > 1, nil
+}
@@ -0,0 +1,52 @@
+//go:build unix
+
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.bin"), make([]byte, 64<<10), 0o644)
+	if err := os.Link(filepath.Join(dir, "a.bin"), filepath.Join(dir, "link.bin")); err != nil {
+		t.Skipf("hard links unsupported: %v", err)
+	}
+	sparse, err := os.Create(filepath.Join(dir, "sparse.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sparse.Truncate(64 << 20)
+	sparse.Close()
+
+	bytes, inodes, err := DiskUsage(dir)
+	if err != nil {
+		t.Fatalf("DiskUsage error: %v", err)
+	}
+	if inodes != 3 { // dir, a.bin (twice linked), sparse.bin
+		t.Errorf("expected 3 inodes, got %d", inodes)
+	}
+	if bytes < 64<<10 || bytes >= 64<<20 {
+		t.Errorf("expected hard link counted once and sparse file not allocated, got %d bytes", bytes)
+	}
+
+	if _, _, err := DiskUsage(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing dir")
+	}
+}
+
+func TestReportDiskUsage(t *testing.T) {
+	mem := fstest.MapFS{"root/a.txt": {Data: []byte("A")}, "root/sub/b.txt": {Data: []byte("B")}}
+	ex, err := Extract(mem, "root", "du", t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+	rep := ex.Report()
+	if rep.Inodes != 4 || rep.DiskBytes <= 0 {
+		t.Errorf("expected 4 inodes and positive disk usage, got %+v", rep)
+	}
+}
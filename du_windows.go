@@ -0,0 +1,41 @@
+package efs
+
+import (
+	"io/fs"
+	"syscall"
+	"unsafe"
+)
+
+var procGetCompressedFileSizeW = syscall.NewLazyDLL("kernel32.dll").NewProc("GetCompressedFileSizeW")
+
+// entryUsage returns the identity and allocated size of the entry at path.
+// linked reports whether the entry has further hard links that must not be
+// counted twice.
+func entryUsage(path string, info fs.FileInfo) (id fileID, size int64, linked bool, err error) {
+	if info.IsDir() || !info.Mode().IsRegular() {
+		return id, 0, false, nil
+	}
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return id, 0, false, err
+	}
+	size = info.Size()
+	var high uint32
+	low, _, callErr := procGetCompressedFileSizeW.Call(uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&high)))
+	if uint32(low) != 0xFFFFFFFF || callErr == syscall.Errno(0) {
+		size = int64(high)<<32 | int64(uint32(low))
+	}
+
+	h, err := syscall.CreateFile(p, 0, syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE,
+		nil, syscall.OPEN_EXISTING, syscall.FILE_FLAG_BACKUP_SEMANTICS|syscall.FILE_FLAG_OPEN_REPARSE_POINT, 0)
+	if err != nil {
+		return id, size, false, nil
+	}
+	defer syscall.CloseHandle(h)
+	var fi syscall.ByHandleFileInformation
+	if syscall.GetFileInformationByHandle(h, &fi) != nil {
+		return id, size, false, nil
+	}
+	id = fileID{dev: uint64(fi.VolumeSerialNumber), ino: uint64(fi.FileIndexHigh)<<32 | uint64(fi.FileIndexLow)}
+	return id, size, fi.NumberOfLinks > 1, nil
+}
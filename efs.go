@@ -7,19 +7,26 @@
 //   - Each temp directory has a unique name based on the prefix and a random suffix.
 //   - It's the caller's responsibility to call cleanup() to remove temp directories.
 //   - Use StartCleanupListener() to automatically clean up on program termination signals.
-//   - By default, temp directories are created in the current working directory.
+//   - By default, temp directories are created in os.TempDir(). Call
+//     SetDefaultBaseDir(".") to restore the historical working-directory default.
 //   - You can specify a custom base directory using the tempDir parameter (empty string = default).
+//
+// Under GOOS=js the package builds, but extraction needs a host file system
+// such as Node.js provides. In a browser, file operations fail with errors
+// matching errors.ErrUnsupported.
 package efs
 
 import (
+	"cmp"
 	"fmt"
 	"io/fs"
 	"os"
 	"os/signal"
+	"path"
 	"path/filepath"
-	"strings"
 	"sync"
 	"syscall"
+	"time"
 )
 
 // ExtractToTemp walks the provided filesystem (embed.FS or any fs.FS) starting at
@@ -28,91 +35,46 @@ import (
 // Parameters:
 //   - fsys: The filesystem to extract from (embed.FS, fstest.MapFS, os.DirFS, etc.)
 //   - root: The root path within fsys to extract (empty string defaults to ".")
-//   - tempPrefix: Prefix for the temporary directory name
-//   - tempDir: Base directory where temp dir will be created (empty string = current working directory)
+//   - tempPrefix: Prefix for the temporary directory name; it must not contain
+//     path separators or control characters (see ErrInvalidPrefix)
+//   - tempDir: Base directory where temp dir will be created (empty string = DefaultBaseDir())
+//   - opts: Optional behavior such as WithOwner
 //
 // Behavior:
 //   - If root is empty, "." is used.
 //   - The returned temp directory contains the CONTENTS of root (the root folder
 //     itself is not created inside the temp directory).
+//   - Empty source directories are recreated unless WithSkipEmptyDirs is given.
+//   - If root is a regular file, the temp directory contains just that file
+//     under its base name (root "assets/app.bin" yields <dir>/app.bin).
 //   - Each call creates a NEW temporary directory with a unique name.
 //   - Returns: absolute temp directory path, an idempotent cleanup func, and error.
+//   - Use Extract for a handle with additional methods (Open, Verify, Report).
 //
 // Example:
 //
 //	dir, cleanup, err := ExtractToTemp(assets, "assets", "myassets", "")
 //	defer cleanup()
-func ExtractToTemp(fsys fs.FS, root string, tempPrefix string, tempDir string) (string, func(), error) {
-	if root == "" {
-		root = "."
-	}
-
-	// Use current working directory if tempDir is empty
-	baseDir := tempDir
-	if baseDir == "" {
-		baseDir = "."
-	}
-
-	// Create a temporary directory in the specified base directory
-	temp, err := os.MkdirTemp(baseDir, tempPrefix+"-")
-	if err != nil {
-		return "", nil, fmt.Errorf("create temp dir: %w", err)
-	}
-	absTempDir, absErr := filepath.Abs(temp)
-	if absErr != nil {
-		// Fallback to relative path if Abs fails
-		absTempDir = temp
-	}
-
-	// Idempotent cleanup
-	var once sync.Once
-	cleanup := func() {
-		once.Do(func() { _ = os.RemoveAll(absTempDir) })
-	}
-
-	// Walk and extract
-	err = fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
-		}
-
-		// Skip creating the top-level root dir inside temp; only its contents
-		if path == root && d.IsDir() {
-			return nil
-		}
-
-		// Build relative path (strip leading "root/" if root != ".")
-		rel := path
-		if root != "." && root != "" {
-			if r, ok := strings.CutPrefix(path, root+"/"); ok {
-				rel = r
-			} else if path == root { // safety, though handled above
-				rel = "."
-			}
-		}
-
-		dst := filepath.Join(absTempDir, rel)
-		if d.IsDir() {
-			return os.MkdirAll(dst, 0o755)
-		}
-
-		// Ensure parent dirs exist (robust even if Walk order changes)
-		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
-			return err
-		}
-
-		data, err := fs.ReadFile(fsys, path)
-		if err != nil {
-			return err
-		}
-		return os.WriteFile(dst, data, 0o644)
-	})
+func ExtractToTemp(fsys fs.FS, root string, tempPrefix string, tempDir string, opts ...Option) (string, func(), error) {
+	e, err := Extract(fsys, root, tempPrefix, tempDir, opts...)
 	if err != nil {
-		cleanup() // Clean up if extraction fails
 		return "", nil, err
 	}
+	return e.Dir(), func() { _ = e.Cleanup() }, nil
+}
 
-	return absTempDir, cleanup, nil
+// ExtractToTempOpt is ExtractToTemp with the base directory moved into the
+// options (see WithTempDir), so that new capabilities can be added as options
+// without changing the signature again. It returns the same values as
+// ExtractToTemp.
+//
+// Example:
+//
+//	dir, cleanup, err := ExtractToTempOpt(assets, "assets", "myassets",
+//		WithTempDir(os.TempDir()), WithExactPerms())
+//	defer cleanup()
+func ExtractToTempOpt(fsys fs.FS, root string, tempPrefix string, opts ...Option) (string, func(), error) {
+	return ExtractToTemp(fsys, root, tempPrefix, "", opts...)
 }
 
 // ExtractFile extracts a single file from the provided filesystem into a temporary file.
@@ -120,8 +82,9 @@ func ExtractToTemp(fsys fs.FS, root string, tempPrefix string, tempDir string) (
 // Parameters:
 //   - fsys: The filesystem to extract from (embed.FS, fstest.MapFS, os.DirFS, etc.)
 //   - filePath: The path to the file within fsys to extract
-//   - tempPrefix: Prefix for the temporary file name
-//   - tempDir: Base directory where temp file will be created (empty string = current working directory)
+//   - tempPrefix: Prefix for the temporary file name (see ErrInvalidPrefix)
+//   - tempDir: Base directory where temp file will be created (empty string = DefaultBaseDir())
+//   - opts: Optional behavior such as WithOwner
 //
 // Behavior:
 //   - Creates a new temporary file with a unique name.
@@ -133,37 +96,65 @@ func ExtractToTemp(fsys fs.FS, root string, tempPrefix string, tempDir string) (
 //
 //	file, cleanup, err := ExtractFile(assets, "assets/config.json", "config", "")
 //	defer cleanup()
-func ExtractFile(fsys fs.FS, filePath string, tempPrefix string, tempDir string) (string, func(), error) {
-	// Use current working directory if tempDir is empty
-	baseDir := tempDir
-	if baseDir == "" {
-		baseDir = "."
+func ExtractFile(fsys fs.FS, filePath string, tempPrefix string, tempDir string, opts ...Option) (_ string, _ func(), err error) {
+	start := time.Now()
+	o := newOptions(opts)
+	defer func() { o.reportError(err, "extract file", false) }()
+	namePrefix := o.assignID(tempPrefix)
+	if err := validatePrefix(namePrefix); err != nil {
+		return "", nil, err
 	}
 
-	// Read the file from the filesystem
-	data, err := fs.ReadFile(fsys, filePath)
+	if err := o.context().Err(); err != nil {
+		return "", nil, err
+	}
+
+	// Stat the source first; its contents are streamed into the temp file
+	info, err := fs.Stat(fsys, filePath)
 	if err != nil {
-		return "", nil, fmt.Errorf("read file %q: %w", filePath, err)
+		return "", nil, o.redactErr(sourceErr(filePath, err), filePath)
 	}
+	size := info.Size()
 
-	// Create a temporary file
-	// Extract extension from original filename if present
-	ext := filepath.Ext(filePath)
-	tempFile, err := os.CreateTemp(baseDir, tempPrefix+"-*"+ext)
+	baseDir, err := o.execBase(o.tempBase(tempDir, size), o.isExecutable(filePath) || o.inExecTree(filePath))
 	if err != nil {
-		return "", nil, fmt.Errorf("create temp file: %w", err)
+		return "", nil, err
+	}
+	if err := o.checkSpace(baseDir, size); err != nil {
+		return "", nil, err
 	}
 
-	// Write data to temp file
-	if _, err := tempFile.Write(data); err != nil {
-		tempFile.Close()
-		os.Remove(tempFile.Name())
-		return "", nil, fmt.Errorf("write temp file: %w", err)
+	// Create a temporary file
+	// Extract extension from original filename if present (".exe" for
+	// executables on Windows)
+	ext := path.Ext(o.exeName(filePath))
+	var tempFile *os.File
+	baseDir, err = o.createInBase(baseDir, tempDir, func(dir string) (err error) {
+		if dir, err = o.isolate(dir); err != nil {
+			return err
+		}
+		tempFile, err = o.createTemp(dir, namePrefix, ext)
+		return err
+	})
+	if err != nil {
+		return "", nil, &DestError{Path: baseDir, Err: fmt.Errorf("create temp file: %w", err)}
 	}
 
-	if err := tempFile.Close(); err != nil {
+	n, digest, err := writeTempFile(tempFile, fsys, filePath, o)
+	err = destErr(tempFile.Name(), err)
+	if err == nil {
+		err = destErr(tempFile.Name(), o.applyPrivateACL(tempFile.Name(), false))
+	}
+	err = withDiskFull(err, baseDir, 0, size)
+	if auditErr := o.recordFile(filePath, "", tempFile.Name(), n, digest, err); err == nil {
+		err = auditErr
+	}
+	if err == nil {
+		err = o.context().Err() // Canceled while reading or writing
+	}
+	if err != nil {
 		os.Remove(tempFile.Name())
-		return "", nil, fmt.Errorf("close temp file: %w", err)
+		return "", nil, o.redactErr(err, filePath)
 	}
 
 	absFilePath, absErr := filepath.Abs(tempFile.Name())
@@ -171,37 +162,122 @@ func ExtractFile(fsys fs.FS, filePath string, tempPrefix string, tempDir string)
 		// Fallback to relative path if Abs fails
 		absFilePath = tempFile.Name()
 	}
+	(&softLimits{}).add(o, filepath.Base(absFilePath), n)
+	if o.clearQuarantine {
+		clearQuarantine([]string{absFilePath})
+	}
+
+	if o.strictPerms {
+		if err := CheckPermissions(absFilePath); err != nil {
+			os.Remove(absFilePath)
+			return "", nil, err
+		}
+	}
+	if o.immutable {
+		if err := makeImmutable([]string{absFilePath}); err != nil {
+			os.Remove(absFilePath)
+			return "", nil, o.redactErr(err, filePath)
+		}
+	}
 
 	// Idempotent cleanup
+	trackCreate(absFilePath)
 	var once sync.Once
 	cleanup := func() {
-		once.Do(func() { _ = os.Remove(absFilePath) })
+		once.Do(func() {
+			stats := CleanupStats{ID: o.id, Path: absFilePath, Files: 1}
+			if o.onCleanup != nil {
+				stats.DiskBytes, _, _ = DiskUsage(absFilePath)
+			}
+			if o.immutable {
+				clearImmutable([]string{absFilePath})
+			}
+			err := o.traceOp("cleanup", absFilePath, func() error { return os.Remove(absFilePath) })
+			trackDone(absFilePath)
+			if o.onCleanup != nil {
+				stats.Alive, stats.Err = time.Since(start), err
+				o.onCleanup(stats)
+			}
+		})
 	}
 
 	return absFilePath, cleanup, nil
 }
 
+// writeTempFile streams filePath from fsys into the freshly created
+// tempFile, closes it and applies the per-file options. It returns the number
+// of bytes written and, with WithAuditLog, their digest.
+func writeTempFile(tempFile *os.File, fsys fs.FS, filePath string, o *options) (n int64, digest string, err error) {
+	err = o.traceOp("write", tempFile.Name(), func() error {
+		n, digest, err = o.copySource(fsys, filePath, tempFile)
+		return err
+	})
+	if err != nil {
+		tempFile.Close()
+		if classified(err) {
+			return n, "", err
+		}
+		return n, "", fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return n, "", fmt.Errorf("close temp file: %w", err)
+	}
+
+	// os.CreateTemp always creates 0o600, so an explicit file mode can only be
+	// honored with a Chmod; it is applied literally regardless of the umask.
+	mode := o.fileMode
+	if o.isExecutable(filePath) || o.inExecTree(filePath) {
+		mode = execPerm(cmp.Or(mode, 0o600))
+	}
+	if mode != 0 {
+		if err := o.traceOp("chmod", tempFile.Name(), func() error { return os.Chmod(tempFile.Name(), mode) }); err != nil {
+			return n, "", fmt.Errorf("chmod temp file: %w", err)
+		}
+	}
+
+	if err := o.finish(fsys, filePath, tempFile.Name()); err != nil {
+		return n, "", fmt.Errorf("apply file metadata: %w", err)
+	}
+	return n, digest, nil
+}
+
+// Hooks for StartCleanupListener, replaced in tests.
+var (
+	exit      = os.Exit
+	removeAll = os.RemoveAll
+)
+
 // StartCleanupListener starts a goroutine that listens for shutdown signals (e.g., Ctrl+C or SIGTERM)
 // and cleans up the specified directory before exiting the program.
 // It returns a stop function to disable the listener when you no longer need it.
 // Note: os.Exit is called after cleanup, which skips other defers by design.
+//
+// Signals escalate like in well-behaved daemons: the first one starts the
+// cleanup, and a second one received while a large tree is still being
+// removed exits at once, leaving the rest of dir behind.
 func StartCleanupListener(dir string) (stop func()) {
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, cleanupSignals...)
 
 	stopped := make(chan struct{})
 	go func() {
 		select {
 		case sig := <-sigCh:
 			fmt.Printf("Received signal %v, cleaning up %s\n", sig, dir)
-			if err := os.RemoveAll(dir); err != nil {
-				fmt.Printf("Error cleaning up %s: %v\n", dir, err)
-			}
-			if s, ok := sig.(syscall.Signal); ok {
-				os.Exit(128 + int(s))
-			} else {
-				os.Exit(1)
+			cleaned := make(chan struct{})
+			go func() {
+				defer close(cleaned)
+				if err := removeAll(dir); err != nil {
+					fmt.Printf("Error cleaning up %s: %v\n", dir, err)
+				}
+			}()
+			select {
+			case <-cleaned:
+			case sig = <-sigCh:
+				fmt.Printf("Received signal %v again, exiting without finishing cleanup of %s\n", sig, dir)
 			}
+			exit(exitCode(sig))
 		case <-stopped:
 			return
 		}
@@ -212,3 +288,11 @@ func StartCleanupListener(dir string) (stop func()) {
 		signal.Stop(sigCh)
 	}
 }
+
+// exitCode returns the conventional exit status for termination by sig.
+func exitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
@@ -400,3 +400,28 @@ func TestMultipleExtractionsCreateSeparateDirs(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractFileAsRoot(t *testing.T) {
+	mem := fstest.MapFS{
+		"assets/app.bin":   {Data: []byte("BIN")},
+		"assets/other.txt": {Data: []byte("other")},
+	}
+
+	dir, cleanup, err := ExtractToTemp(mem, "assets/app.bin", "fileroot", t.TempDir())
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+
+	data, err := os.ReadFile(filepath.Join(dir, "app.bin"))
+	if err != nil {
+		t.Fatalf("expected app.bin in temp dir: %v", err)
+	}
+	if string(data) != "BIN" {
+		t.Errorf("expected %q, got %q", "BIN", data)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected only app.bin, got %d entries", len(entries))
+	}
+}
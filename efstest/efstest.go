@@ -0,0 +1,76 @@
+// Package efstest provides helpers for tests of code that extracts files
+// with efs.
+package efstest
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	efs "github.com/skabbio1976/eFS"
+)
+
+// VerifyNoLeaks fails t if entries created in dirs during the test are still
+// there when it ends, which is what an extraction whose Cleanup was never
+// called leaves behind. Without dirs it watches efs.DefaultBaseDir(). It
+// snapshots the directories when called and compares them in a t.Cleanup
+// function, so it should be called at the start of the test, before
+// t.TempDir, whose directories are removed before the comparison runs.
+//
+// Entries created by anything else in the watched directories count as
+// leaks too, so tests that run in parallel with others writing to the same
+// directory should watch a dedicated one, for example by passing
+// efs.WithTempDir(t.TempDir()) to the code under test and watching that.
+//
+// Example:
+//
+//	func TestServe(t *testing.T) {
+//		efstest.VerifyNoLeaks(t)
+//		dir, cleanup, err := efs.ExtractToTemp(assets, "assets", "serve", "")
+//		...
+//	}
+func VerifyNoLeaks(t testing.TB, dirs ...string) {
+	t.Helper()
+	if len(dirs) == 0 {
+		dirs = []string{efs.DefaultBaseDir()}
+	}
+	before := make([][]string, len(dirs))
+	for i, dir := range dirs {
+		names, err := entries(dir)
+		if err != nil {
+			t.Fatalf("efstest: snapshot %s: %v", dir, err)
+		}
+		before[i] = names
+	}
+	t.Cleanup(func() {
+		t.Helper()
+		for i, dir := range dirs {
+			names, err := entries(dir)
+			if err != nil {
+				t.Errorf("efstest: list %s: %v", dir, err)
+				continue
+			}
+			for _, name := range names {
+				if _, found := slices.BinarySearch(before[i], name); !found {
+					t.Errorf("efstest: leaked %s; was Cleanup called?", filepath.Join(dir, name))
+				}
+			}
+		}
+	})
+}
+
+// entries returns the sorted names in dir; a missing dir has none.
+func entries(dir string) ([]string, error) {
+	list, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	names := make([]string, len(list))
+	for i, e := range list {
+		names[i] = e.Name()
+	}
+	return names, nil
+}
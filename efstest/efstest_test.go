@@ -0,0 +1,73 @@
+package efstest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	efs "github.com/skabbio1976/eFS"
+)
+
+// recorder is a testing.TB that collects failures and cleanups instead of
+// acting on them.
+type recorder struct {
+	testing.TB
+	errs     []string
+	cleanups []func()
+}
+
+func (r *recorder) Helper()           {}
+func (r *recorder) Cleanup(fn func()) { r.cleanups = append(r.cleanups, fn) }
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+func (r *recorder) Fatalf(format string, args ...any) { r.Errorf(format, args...) }
+
+// finish runs the recorded cleanups in reverse, as testing does.
+func (r *recorder) finish() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestVerifyNoLeaks(t *testing.T) {
+	base := t.TempDir()
+	mem := fstest.MapFS{"assets/a.txt": {Data: []byte("A")}}
+
+	r := &recorder{TB: t}
+	VerifyNoLeaks(r, base)
+	ex, err := efs.Extract(mem, "assets", "kept", base)
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	leaked := ex.Dir()
+	ex, err = efs.Extract(mem, "assets", "cleaned", base)
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	ex.Cleanup()
+	r.finish()
+
+	if len(r.errs) != 1 || !strings.Contains(r.errs[0], leaked) {
+		t.Errorf("expected one leak report for %s, got %q", leaked, r.errs)
+	}
+}
+
+func TestVerifyNoLeaksClean(t *testing.T) {
+	base := t.TempDir()
+	mem := fstest.MapFS{"assets/a.txt": {Data: []byte("A")}}
+
+	r := &recorder{TB: t}
+	VerifyNoLeaks(r, base)
+	_, cleanup, err := efs.ExtractToTemp(mem, "assets", "clean", base)
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	cleanup()
+	r.finish()
+
+	if len(r.errs) != 0 {
+		t.Errorf("expected no leaks, got %q", r.errs)
+	}
+}
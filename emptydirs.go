@@ -0,0 +1,34 @@
+package efs
+
+import (
+	"io/fs"
+	"path"
+)
+
+// WithSkipEmptyDirs leaves out directories that contain no files, directly
+// or further down, for callers who only care about files. By default every
+// source directory is recreated, including empty ones.
+func WithSkipEmptyDirs() Option {
+	return func(o *options) { o.skipEmptyDirs = true }
+}
+
+// dropEmptyDirs removes directory entries without any file below them.
+func dropEmptyDirs(entries []planEntry) []planEntry {
+	used := make(map[string]bool)
+	for _, e := range entries {
+		if e.d.IsDir() {
+			continue
+		}
+		for dir := path.Dir(e.rel); dir != "." && !used[dir]; dir = path.Dir(dir) {
+			used[dir] = true
+		}
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		// Junctions kept by WithReparsePoints have no entries below them.
+		if !e.d.IsDir() || used[e.rel] || e.d.Type()&fs.ModeIrregular != 0 {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,46 @@
+package efs
+
+import "errors"
+
+// SourceError reports a failure to read from the source file system, such as
+// a missing root or an unreadable file. It wraps the underlying error, so
+// errors.Is(err, fs.ErrNotExist) works for missing sources.
+type SourceError struct {
+	Path string // Slash-separated path within the source fs.FS
+	Err  error
+}
+
+func (e *SourceError) Error() string { return "read source: " + e.Err.Error() }
+func (e *SourceError) Unwrap() error { return e.Err }
+
+// DestError reports a failure to create or write something on disk, such as
+// the temp directory, an extracted file or its metadata.
+type DestError struct {
+	Path string // Native path on disk
+	Err  error
+}
+
+func (e *DestError) Error() string { return "write destination: " + e.Err.Error() }
+func (e *DestError) Unwrap() error { return e.Err }
+
+// sourceErr wraps err as a *SourceError unless it is nil or already classified.
+func sourceErr(path string, err error) error {
+	if err == nil || classified(err) {
+		return err
+	}
+	return &SourceError{Path: path, Err: err}
+}
+
+// destErr wraps err as a *DestError unless it is nil or already classified.
+func destErr(path string, err error) error {
+	if err == nil || classified(err) {
+		return err
+	}
+	return &DestError{Path: path, Err: err}
+}
+
+func classified(err error) bool {
+	var se *SourceError
+	var de *DestError
+	return errors.As(err, &se) || errors.As(err, &de)
+}
@@ -0,0 +1,47 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestErrorsIsNotExist(t *testing.T) {
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+
+	_, _, err := ExtractToTemp(mem, "missing", "errs", t.TempDir())
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing root: expected fs.ErrNotExist, got %v", err)
+	}
+	var se *SourceError
+	if !errors.As(err, &se) || se.Path != "missing" {
+		t.Errorf("missing root: expected *SourceError for %q, got %v", "missing", err)
+	}
+
+	_, _, err = ExtractFile(mem, "missing.txt", "errs", t.TempDir())
+	if !errors.Is(err, fs.ErrNotExist) || !errors.As(err, &se) {
+		t.Errorf("missing file: expected *SourceError wrapping fs.ErrNotExist, got %v", err)
+	}
+}
+
+func TestDestErrorClassification(t *testing.T) {
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+
+	// A base directory that is a regular file cannot hold a temp dir.
+	notDir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notDir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := ExtractToTemp(mem, ".", "errs", notDir)
+	var de *DestError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected *DestError, got %v", err)
+	}
+	var se *SourceError
+	if errors.As(err, &se) {
+		t.Errorf("destination failure must not be a *SourceError: %v", err)
+	}
+}
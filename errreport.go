@@ -0,0 +1,50 @@
+package efs
+
+import "errors"
+
+// ErrorContext describes where a failure passed to a WithErrorReporter
+// function occurred.
+type ErrorContext struct {
+	Op      string // "extract", "extract file", "apply" or "cleanup"
+	ID      string // Extraction ID (see WithExtractionID); empty for Plan.Apply without one
+	Source  string // Source path involved, if known, subject to WithRedaction
+	Dest    string // Path on disk involved, if known
+	Skipped bool   // The entry was left out under WithContinueOnError and extraction went on
+}
+
+// WithErrorReporter calls fn for every failure of an extraction: the error
+// returned by Extract, ExtractFile, Plan.Apply and Cleanup, errors of a
+// WithPriority extraction in the background, and each entry left out under
+// WithContinueOnError. This routes extraction problems into error tracking
+// or alerting without wrapping every call. fn runs synchronously on the
+// extracting goroutine and should not block; a background extraction
+// stopped by Cleanup is not reported.
+func WithErrorReporter(fn func(err error, ec ErrorContext)) Option {
+	return func(o *options) { o.errReporter = fn }
+}
+
+// WithContinueOnError keeps extracting when a single entry cannot be read or
+// written: the entry is listed in Report.Failed, reported to the
+// WithErrorReporter function and skipped, and the extraction succeeds with
+// the rest. A failed file may be left partially written. Failures outside
+// individual entries, such as creating the temp directory, still abort.
+func WithContinueOnError() Option {
+	return func(o *options) { o.continueOnError = true }
+}
+
+// reportError passes err to the WithErrorReporter function, if any.
+func (o *options) reportError(err error, op string, skipped bool) {
+	if err == nil || o.errReporter == nil {
+		return
+	}
+	ec := ErrorContext{Op: op, ID: o.id, Skipped: skipped}
+	var se *SourceError
+	if errors.As(err, &se) {
+		ec.Source = o.display(se.Path)
+	}
+	var de *DestError
+	if errors.As(err, &de) {
+		ec.Dest = de.Path
+	}
+	o.errReporter(err, ec)
+}
@@ -0,0 +1,66 @@
+package efs
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithContinueOnError(t *testing.T) {
+	bad := badFS{base: fstest.MapFS{
+		"assets/a.txt": {Data: []byte("A")},
+		"assets/b.txt": {Data: []byte("B")},
+		"assets/c.txt": {Data: []byte("C")},
+	}, fail: "assets/b.txt"}
+
+	type report struct {
+		err error
+		ec  ErrorContext
+	}
+	var reports []report
+	reporter := WithErrorReporter(func(err error, ec ErrorContext) {
+		reports = append(reports, report{err, ec})
+	})
+
+	ex, err := Extract(bad, "assets", "cont", t.TempDir(), reporter, WithContinueOnError(), WithExtractionID("job-7"))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	if rep := ex.Report(); rep.Files != 2 || !slices.Equal(rep.Failed, []string{"b.txt"}) {
+		t.Errorf("expected two files and b.txt failed, got %+v", rep)
+	}
+	if _, err := os.Stat(ex.Path("c.txt")); err != nil {
+		t.Errorf("expected extraction to continue past the failure: %v", err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("expected one report, got %+v", reports)
+	}
+	ec := reports[0].ec
+	if !ec.Skipped || ec.Op != "extract" || ec.Source != "assets/b.txt" || ec.ID != "job-7" {
+		t.Errorf("unexpected error context %+v", ec)
+	}
+}
+
+func TestWithErrorReporter(t *testing.T) {
+	bad := badFS{base: fstest.MapFS{"assets/a.txt": {Data: []byte("A")}}, fail: "assets/a.txt"}
+
+	var got []ErrorContext
+	reporter := WithErrorReporter(func(err error, ec ErrorContext) { got = append(got, ec) })
+
+	_, err := Extract(bad, "assets", "rep", t.TempDir(), reporter)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, _, err := ExtractFile(bad, "assets/a.txt", "rep", t.TempDir(), reporter); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(got) != 2 || got[0].Op != "extract" || got[1].Op != "extract file" || got[0].Skipped {
+		t.Errorf("unexpected reports %+v", got)
+	}
+	if got[1].Source != "assets/a.txt" {
+		t.Errorf("expected the failing source in the context, got %+v", got[1])
+	}
+}
@@ -0,0 +1,111 @@
+package efs
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// WithExecutables marks the files whose destination path matches one of
+// patterns as programs meant to be run. On Unix they get the execute bit
+// wherever the mode grants read access (0o644 becomes 0o755). On Windows they
+// are extracted with an ".exe" suffix unless they already have one, so an
+// embedded "bin/tool" becomes "bin/tool.exe". Use Extraction.Executable to
+// get the platform-correct path. Patterns use the syntax of
+// WithWindowsAttributes.
+func WithExecutables(patterns ...string) Option {
+	return func(o *options) { o.executables = append(o.executables, patterns...) }
+}
+
+// WithCmdShims additionally writes a "<name>.cmd" next to every executable on
+// Windows that forwards its arguments to the ".exe", for callers and scripts
+// that invoke tools by their bare name through cmd.exe. It has no effect on
+// other platforms.
+func WithCmdShims() Option {
+	return func(o *options) { o.cmdShims = true }
+}
+
+// WithExecAll marks every file in the subtrees matching one of globs as
+// executable, for bundled toolchains whose bin directories, plugin folders
+// or node_modules/.bin hold many programs and scripts: "toolchain/bin"
+// covers everything below toolchain/bin, as does "toolchain/bin/**". A glob
+// may also match files directly. Unlike WithExecutables it only sets the
+// execute bit and never renames files, so it has no effect on Windows.
+// Patterns use the syntax of WithWindowsAttributes.
+func WithExecAll(globs ...string) Option {
+	return func(o *options) { o.execAll = append(o.execAll, globs...) }
+}
+
+// inExecTree reports whether the destination path rel or one of its parent
+// directories matches a WithExecAll glob.
+func (o *options) inExecTree(rel string) bool {
+	if len(o.execAll) == 0 {
+		return false
+	}
+	for p := rel; p != "." && p != "/"; p = path.Dir(p) {
+		if matchAny(o.execAll, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// wantsExecBit reports whether the file at destination path rel gets the
+// execute bit.
+func (o *options) wantsExecBit(rel string) bool {
+	return o.isExtractedExecutable(rel) || o.inExecTree(rel)
+}
+
+// isExecutable reports whether the destination path rel is a program.
+func (o *options) isExecutable(rel string) bool {
+	return len(o.executables) > 0 && matchAny(o.executables, rel)
+}
+
+// isExtractedExecutable reports whether the destination path rel, as
+// returned by exeName, is a program.
+func (o *options) isExtractedExecutable(rel string) bool {
+	if o.isExecutable(rel) {
+		return true
+	}
+	name, ok := strings.CutSuffix(rel, ".exe")
+	return ok && runtime.GOOS == "windows" && o.isExecutable(name)
+}
+
+// exeName returns the platform-specific destination path of the file rel.
+func (o *options) exeName(rel string) string {
+	if runtime.GOOS != "windows" || !o.isExecutable(rel) || strings.EqualFold(path.Ext(rel), ".exe") {
+		return rel
+	}
+	return rel + ".exe"
+}
+
+// execPerm returns the mode for an executable file: perm with the execute
+// bit added wherever it grants read access.
+func execPerm(perm fs.FileMode) fs.FileMode {
+	return perm | (perm&0o444)>>2
+}
+
+// shimName returns the destination path of the .cmd shim for the file
+// extracted at rel, or "" if no shim is written for it.
+func (o *options) shimName(rel string) string {
+	if !o.cmdShims || runtime.GOOS != "windows" || !strings.EqualFold(path.Ext(rel), ".exe") || !o.isExtractedExecutable(rel) {
+		return ""
+	}
+	return strings.TrimSuffix(rel, path.Ext(rel)) + ".cmd"
+}
+
+// writeShim writes the .cmd shim for the executable at dst.
+func (o *options) writeShim(dst, shim string) error {
+	script := "@\"%~dp0" + filepath.Base(dst) + "\" %*\r\n"
+	return os.WriteFile(shim, []byte(script), o.filePerm())
+}
+
+// Executable returns the on-disk path of the executable extracted from the
+// slash-separated path name (relative to the extraction root), including the
+// ".exe" suffix WithExecutables adds on Windows.
+func (e *Extraction) Executable(name string) string {
+	return e.Path(e.o.exeName(name))
+}
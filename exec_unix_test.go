@@ -0,0 +1,74 @@
+//go:build unix
+
+package efs
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithExecutables(t *testing.T) {
+	mem := fstest.MapFS{
+		"bin/tool":       {Data: []byte("#!/bin/sh\n")},
+		"share/data.txt": {Data: []byte("D")},
+	}
+	e, err := Extract(mem, ".", "exec", t.TempDir(), WithExecutables("bin/*"), WithExactPerms())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+
+	if got := e.Executable("bin/tool"); got != e.Path("bin/tool") {
+		t.Errorf("expected unchanged name on Unix, got %s", got)
+	}
+	for name, want := range map[string]os.FileMode{"bin/tool": 0o755, "share/data.txt": 0o644} {
+		fi, err := os.Stat(e.Path(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != want {
+			t.Errorf("%s: expected %o, got %o", name, want, fi.Mode().Perm())
+		}
+	}
+	if err := e.Verify(); err != nil {
+		t.Errorf("Verify error: %v", err)
+	}
+
+	file, cleanup, err := ExtractFile(mem, "bin/tool", "exec", t.TempDir(), WithExecutables("tool"))
+	if err != nil {
+		t.Fatalf("ExtractFile error: %v", err)
+	}
+	defer cleanup()
+	if fi, err := os.Stat(file); err != nil || fi.Mode().Perm() != 0o700 {
+		t.Errorf("expected 0700 temp executable, got %v (err=%v)", fi.Mode().Perm(), err)
+	}
+}
+
+func TestWithExecAll(t *testing.T) {
+	mem := fstest.MapFS{
+		"toolchain/bin/cc":                   {Data: []byte("ELF")},
+		"toolchain/bin/plugins/protoc-gen-x": {Data: []byte("ELF")},
+		"toolchain/lib/libc.a":               {Data: []byte("AR")},
+		"node_modules/.bin/tsc":              {Data: []byte("#!/usr/bin/env node")},
+	}
+	e, err := Extract(mem, ".", "execall", t.TempDir(), WithExecAll("toolchain/bin", "**/.bin/*"), WithExactPerms())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+	for name, want := range map[string]os.FileMode{
+		"toolchain/bin/cc":                   0o755,
+		"toolchain/bin/plugins/protoc-gen-x": 0o755,
+		"toolchain/lib/libc.a":               0o644,
+		"node_modules/.bin/tsc":              0o755,
+	} {
+		fi, err := os.Stat(e.Path(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != want {
+			t.Errorf("%s: expected %o, got %o", name, want, fi.Mode().Perm())
+		}
+	}
+}
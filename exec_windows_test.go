@@ -0,0 +1,34 @@
+package efs
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithExecutablesWindows(t *testing.T) {
+	mem := fstest.MapFS{
+		"bin/tool":      {Data: []byte("MZ")},
+		"bin/other.exe": {Data: []byte("MZ")},
+	}
+	e, err := Extract(mem, ".", "exec", t.TempDir(), WithExecutables("bin/*"), WithCmdShims())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+
+	if got := e.Executable("bin/tool"); got != e.Path("bin/tool.exe") {
+		t.Errorf("expected .exe suffix, got %s", got)
+	}
+	if got := e.Executable("bin/other.exe"); got != e.Path("bin/other.exe") {
+		t.Errorf("expected existing .exe kept, got %s", got)
+	}
+	shim, err := os.ReadFile(e.Path("bin/tool.cmd"))
+	if err != nil || !strings.Contains(string(shim), `%~dp0tool.exe`) {
+		t.Errorf("expected shim forwarding to tool.exe, got %q (err=%v)", shim, err)
+	}
+	if err := e.Verify(); err != nil {
+		t.Errorf("Verify error: %v", err)
+	}
+}
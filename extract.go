@@ -0,0 +1,395 @@
+package efs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// source maps one subtree of the source file system into the extraction.
+type source struct {
+	root string // Path within fsys
+	dest string // Slash-separated path below the extraction root; "." for the root itself
+}
+
+// rel returns the destination path of the walked entry p below the extraction root.
+func (s source) rel(p string) string {
+	if p == s.root && s.dest == "." {
+		// A regular file given as root lands directly in the extraction
+		// root under its own name (directory roots never get here).
+		return path.Base(p)
+	}
+	return path.Join(s.dest, relPath(s.root, p))
+}
+
+// walkSource walks the subtree src.root of fsys and calls fn for every entry
+// that extraction would materialize, with its source path p and destination
+// path rel below the extraction root. A root extracted to "." is not reported
+// itself; only its contents are. Entries left out by WithInclude, WithExclude
+// or WithFilter are skipped. Errors are redacted.
+func walkSource(fsys fs.FS, src source, o *options, fn func(p, rel string, d fs.DirEntry) error) error {
+	return fs.WalkDir(fsys, src.root, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return o.redactErr(sourceErr(p, walkErr), p)
+		}
+
+		// Skip creating the top-level root dir inside temp; only its contents
+		if p == src.root && d.IsDir() && src.dest == "." {
+			return nil
+		}
+
+		rel := src.rel(p)
+		if o.filtered(rel, d) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		renamed, err := o.renamed(rel, d)
+		if err != nil && err != fs.SkipDir {
+			return o.redactErr(sourceErr(p, err), p, rel)
+		}
+		if renamed == "" {
+			return err
+		}
+		rel = renamed
+		if !d.IsDir() {
+			rel = o.exeName(rel)
+		}
+		if err := o.traceOp("walk", p, func() error { return fn(p, rel, d) }); err != nil {
+			return o.redactErr(err, p, rel)
+		}
+		if d.IsDir() && o.isReparse(fsys, d) {
+			return fs.SkipDir // Handled as a whole by the reparse policy
+		}
+		return nil
+	})
+}
+
+// planEntry is a single entry of a prepared extraction.
+type planEntry struct {
+	src  string      // Path within the source fs.FS
+	rel  string      // Slash-separated destination path below the extraction root
+	d    fs.DirEntry // Entry as reported by the walk
+	size int64       // Size reported by the source; 0 for directories
+}
+
+// prepare walks sources and returns the entries to extract, in walk order
+// unless WithOrdered was given.
+func prepare(fsys fs.FS, sources []source, o *options) ([]planEntry, error) {
+	var entries []planEntry
+	renamed := make(map[string]string) // Destination of each file to its source under WithRename
+	for _, src := range sources {
+		seen := make(map[string]bool)
+		err := walkSource(fsys, src, o, func(p, rel string, d fs.DirEntry) error {
+			if err := o.context().Err(); err != nil {
+				return err
+			}
+			if o.rename != nil && !d.IsDir() {
+				if prev, ok := renamed[rel]; ok {
+					return sourceErr(p, fmt.Errorf("rename: %s and %s both map to %s", prev, p, rel))
+				}
+				renamed[rel] = p
+			}
+			if o.strictSource {
+				if err := checkEntry(fsys, p, d, seen); err != nil {
+					return err
+				}
+			}
+			e := planEntry{src: p, rel: rel, d: d}
+			if !d.IsDir() {
+				info, err := d.Info()
+				if err != nil {
+					return sourceErr(p, err)
+				}
+				e.size = info.Size()
+			}
+			entries = append(entries, e)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if o.skipEmptyDirs || len(o.include) > 0 {
+		entries = dropEmptyDirs(entries)
+	}
+	if o.ordered {
+		sortEntries(entries)
+	}
+	return entries, nil
+}
+
+// planSize returns the total size of the files in entries.
+func planSize(entries []planEntry) int64 {
+	var n int64
+	for _, e := range entries {
+		n += e.size
+	}
+	return n
+}
+
+// applier materializes plan entries for a single extraction run.
+type applier struct {
+	fsys fs.FS
+	o    *options
+	rep  *Report  // Progress is accumulated here
+	j    *journal // Records created entries for rollback; nil when not needed
+	root string   // Destination directory, set by apply
+
+	limits *softLimits // Totals checked against WithSoftLimits; nil to skip
+	prog   *progress   // Reported to WithProgress; nil to skip
+
+	hold func(ctx context.Context) error // Called before each entry, may block; may be nil
+	done func(rel string)                // Called after each entry has been applied; may be nil
+
+	written []string    // Files written, collected for WithClearQuarantine and WithImmutable
+	links   []planEntry // Symlinks created by WithSymlinks, re-checked once all are in place
+
+	mu        sync.Mutex // Guards the bookkeeping above while WithConcurrency workers run
+	order     *reorder   // Holds per-file callbacks under WithConcurrency and WithOrdered; nil otherwise
+	remaining int64      // Size of the entries not applied yet, for DiskFullError
+	y         *yielder   // Spaces out WithBackground extractions; nil otherwise
+}
+
+// apply materializes entries below the existing directory dst, stopping
+// early when ctx is done.
+func (a *applier) apply(ctx context.Context, entries []planEntry, dst string) error {
+	if a.o.clearQuarantine {
+		defer func() { clearQuarantine(a.written) }()
+	}
+	a.root = dst
+	if a.o.lowPriority {
+		a.y = &yielder{}
+	}
+	a.remaining = planSize(entries)
+	if a.o.concurrency > 1 {
+		if err := a.applyParallel(ctx, entries); err != nil {
+			return err
+		}
+	} else {
+		if a.o.lowPriority {
+			defer lowerPriority()()
+		}
+		for _, e := range entries {
+			if err := a.step(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if err := a.checkLinks(); err != nil {
+		return err
+	}
+	if a.o.immutable {
+		return makeImmutable(a.written)
+	}
+	return nil
+}
+
+// step applies the single entry e unless ctx is done.
+func (a *applier) step(ctx context.Context, e planEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if a.hold != nil {
+		if err := a.hold(ctx); err != nil {
+			return err
+		}
+	}
+	err := a.extractEntry(e.src, e.rel, e.d, a.dest(e.rel))
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err != nil {
+		err = withDiskFull(err, a.root, a.rep.Bytes, a.remaining)
+		err = a.o.redactErr(err, e.src, e.rel)
+		if !a.o.continueOnError {
+			return err
+		}
+		a.o.reportError(err, "extract", true)
+		a.rep.Failed = append(a.rep.Failed, e.rel)
+	}
+	a.remaining -= e.size
+	a.prog.finish(e)
+	if a.done != nil {
+		a.done(e.rel)
+	}
+	if a.y != nil {
+		a.y.after(e.size)
+	}
+	return nil
+}
+
+// relPath returns path relative to root (strip leading "root/" if root != ".").
+func relPath(root, path string) string {
+	if root == "." || root == "" {
+		return path
+	}
+	if r, ok := strings.CutPrefix(path, root+"/"); ok {
+		return r
+	}
+	if path == root {
+		return "."
+	}
+	return path
+}
+
+// dest returns the on-disk path of the destination path rel.
+func (a *applier) dest(rel string) string {
+	return filepath.Join(a.root, filepath.FromSlash(rel))
+}
+
+// extractEntry materializes a single walked entry src at dst; rel is the
+// entry's path relative to the extraction root.
+func (a *applier) extractEntry(src, rel string, d fs.DirEntry, dst string) error {
+	o := a.o
+	if o.isReparse(a.fsys, d) {
+		return a.extractReparse(src, rel, dst)
+	}
+	if o.isSymlink(a.fsys, d) {
+		return a.extractSymlink(src, rel, dst)
+	}
+	if err := a.confine(src, rel); err != nil {
+		return err
+	}
+	if d.IsDir() {
+		return destErr(dst, a.extractDir(src, rel, dst))
+	}
+
+	// Ensure parent dirs exist (robust even if Walk order changes)
+	if err := a.mkdirAll(filepath.Dir(dst)); err != nil {
+		return destErr(dst, err)
+	}
+
+	skip, err := o.resolveConflict(dst)
+	if err != nil {
+		return destErr(dst, err)
+	}
+	unchanged := false
+	if !skip && o.conflict == ConflictOverwriteIfDifferent {
+		if unchanged, err = o.unchanged(a.fsys, src, dst); err != nil {
+			return err
+		}
+	}
+	if skip || unchanged {
+		a.mu.Lock()
+		a.rep.Skipped = append(a.rep.Skipped, rel)
+		if unchanged {
+			o.recordSync(rel, true, true)
+		}
+		a.mu.Unlock()
+		return nil
+	}
+	existed := o.existed(dst)
+
+	a.j.create(dst)
+	n, digest, err := a.writeFile(src, rel, dst)
+	return a.fileWritten(src, rel, dst, existed, n, digest, err)
+}
+
+// fileWritten does the bookkeeping for the file src that writeFile wrote to
+// dst with result n, digest and err: the audit record, the report, the soft
+// limits and the WithOnFile callback.
+func (a *applier) fileWritten(src, rel, dst string, existed bool, n int64, digest string, err error) error {
+	o := a.o
+	err = destErr(dst, err)
+	emit := func() error {
+		if auditErr := o.recordFile(src, rel, dst, n, digest, err); auditErr != nil || err != nil {
+			return auditErr
+		}
+		if o.onFile != nil {
+			o.onFile(rel, dst)
+		}
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.order != nil {
+		a.order.hold(rel, emit) // Released in plan order by applyParallel
+	} else if auditErr := emit(); err == nil {
+		err = auditErr
+	}
+	if err != nil {
+		return err
+	}
+	o.recordSync(rel, existed, false)
+	a.rep.Files++
+	a.rep.Bytes += n
+	a.limits.add(o, rel, n)
+	if o.clearQuarantine || o.immutable {
+		a.written = append(a.written, dst)
+	}
+	return nil
+}
+
+// extractDir creates the directory dst for src at destination path rel and
+// applies the per-entry options.
+func (a *applier) extractDir(src, rel, dst string) error {
+	o := a.o
+	if err := a.mkdirAll(dst); err != nil {
+		return err
+	}
+	if err := o.applyPerm(dst, o.dirPerm()); err != nil {
+		return err
+	}
+	a.rep.Dirs++
+	if err := o.finish(a.fsys, src, dst); err != nil {
+		return err
+	}
+	return o.applyWindowsAttributes(rel, dst)
+}
+
+// mkdirAll creates dir and any missing parents, recording them in the journal.
+func (a *applier) mkdirAll(dir string) error {
+	return a.o.traceOp("mkdir", dir, func() error {
+		return a.o.retry(func() error { return a.j.mkdirAll(dir, a.o.dirPerm()) })
+	})
+}
+
+// writeFile streams src to dst at destination path rel and applies the
+// per-file options. It returns the number of bytes written and, with
+// WithAuditLog, their digest.
+func (a *applier) writeFile(src, rel, dst string) (n int64, digest string, err error) {
+	o := a.o
+	perm := o.filePerm()
+	if o.wantsExecBit(rel) {
+		perm = execPerm(perm)
+	}
+	err = o.traceOp("write", dst, func() error {
+		return o.retry(func() error {
+			a.prog.restart(rel) // Count only the last attempt
+			f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+			if err != nil {
+				return err
+			}
+			n, digest, err = o.copySource(a.fsys, src, a.prog.writer(rel, f))
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			return err
+		})
+	})
+	if err != nil {
+		return n, "", err
+	}
+	if err := o.applyPerm(dst, perm); err != nil {
+		return n, "", err
+	}
+	if err := o.finish(a.fsys, src, dst); err != nil {
+		return n, "", err
+	}
+	if err := o.applyWindowsAttributes(rel, dst); err != nil {
+		return n, "", err
+	}
+	if shim := o.shimName(rel); shim != "" {
+		shimPath := a.dest(shim)
+		a.j.create(shimPath)
+		return n, digest, o.writeShim(dst, shimPath)
+	}
+	return n, digest, nil
+}
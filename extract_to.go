@@ -0,0 +1,136 @@
+package efs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// ErrConflict is reported (wrapped in a *DestError) when ConflictError is in
+// effect and a file to be extracted already exists at the destination.
+var ErrConflict = errors.New("destination file already exists")
+
+// ConflictPolicy decides what happens when a file to be extracted already
+// exists in the destination directory.
+type ConflictPolicy int
+
+const (
+	ConflictOverwrite    ConflictPolicy = iota // Replace the existing file (default)
+	ConflictSkipExisting                       // Keep the existing file and skip the source file
+	ConflictError                              // Abort the extraction with ErrConflict
+
+	// ConflictOverwriteIfDifferent replaces the existing file only if its
+	// content differs from the source file, and otherwise skips the source
+	// file, leaving the existing file's modification time and permissions
+	// alone. Symlinks and junctions are replaced as with ConflictOverwrite.
+	ConflictOverwriteIfDifferent
+)
+
+// WithConflict sets the policy for files that already exist at the
+// destination. It matters for ExtractTo; fresh temp directories never
+// conflict. Directories are always merged; the policy only applies to files.
+func WithConflict(policy ConflictPolicy) Option {
+	return func(o *options) { o.conflict = policy }
+}
+
+// ExtractTo extracts the contents of root in fsys into dst, which is created
+// if it does not exist. Unlike ExtractToTemp, dst is caller-owned: it may
+// already contain files (see WithConflict) and is never removed by efs.
+// If root is empty, "." is used; root handling otherwise matches ExtractToTemp.
+//
+// Example:
+//
+//	err := efs.ExtractTo(defaults, "config", userConfigDir, efs.WithConflict(efs.ConflictSkipExisting))
+func ExtractTo(fsys fs.FS, root string, dst string, opts ...Option) error {
+	p, err := Prepare(fsys, root, opts...)
+	if err != nil {
+		return err
+	}
+	return p.Apply(context.Background(), dst)
+}
+
+// ExtractToDir is ExtractTo with the conflict policy for existing files as a
+// parameter, for extracting embedded defaults into a caller-owned directory
+// such as a user's config directory. It is shorthand for ExtractTo with
+// WithConflict(policy); see ConflictPolicy for the choices.
+//
+// Example:
+//
+//	err := efs.ExtractToDir(defaults, "config", userConfigDir, efs.ConflictSkipExisting)
+func ExtractToDir(fsys fs.FS, root string, dst string, policy ConflictPolicy, opts ...Option) error {
+	return ExtractTo(fsys, root, dst, append(opts, WithConflict(policy))...)
+}
+
+// resolveConflict applies the conflict policy to a file about to be written at
+// dst. It reports whether the file should be skipped.
+func (o *options) resolveConflict(dst string) (skip bool, err error) {
+	if o.conflict == ConflictOverwrite || o.conflict == ConflictOverwriteIfDifferent {
+		return false, nil
+	}
+	if _, err := os.Lstat(dst); errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if o.conflict == ConflictSkipExisting {
+		return true, nil
+	}
+	return false, &fs.PathError{Op: "extract", Path: dst, Err: ErrConflict}
+}
+
+// unchanged reports whether dst is a regular file with the same content as
+// the file src in fsys, so ConflictOverwriteIfDifferent can skip it.
+func (o *options) unchanged(fsys fs.FS, src, dst string) (bool, error) {
+	info, err := os.Lstat(dst)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, destErr(dst, err)
+	}
+	if !info.Mode().IsRegular() {
+		return false, nil
+	}
+	srcInfo, err := fs.Stat(fsys, src)
+	if err != nil {
+		return false, sourceErr(src, err)
+	}
+	if srcInfo.Size() != info.Size() {
+		return false, nil
+	}
+
+	f, err := os.Open(dst)
+	if err != nil {
+		return false, destErr(dst, err)
+	}
+	defer f.Close()
+	r, err := o.openSource(fsys, src)
+	if err != nil {
+		return false, sourceErr(src, err)
+	}
+	defer r.Close()
+	bufA, bufB := copyBufs.Get().(*[]byte), copyBufs.Get().(*[]byte)
+	defer copyBufs.Put(bufA)
+	defer copyBufs.Put(bufB)
+	for {
+		n, srcErr := io.ReadFull(r, *bufA)
+		if n > 0 {
+			if _, err := io.ReadFull(f, (*bufB)[:n]); err != nil {
+				return false, nil // dst is shorter than the source
+			}
+			if !bytes.Equal((*bufA)[:n], (*bufB)[:n]) {
+				return false, nil
+			}
+		}
+		switch {
+		case srcErr == io.EOF || srcErr == io.ErrUnexpectedEOF:
+			// Equal only if dst ends here too.
+			k, _ := f.Read((*bufB)[:1])
+			return k == 0, nil
+		case srcErr != nil:
+			return false, sourceErr(src, srcErr)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package efs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestExtractToConflictPolicies(t *testing.T) {
+	mem := fstest.MapFS{
+		"cfg/app.yaml":   {Data: []byte("default")},
+		"cfg/extra.yaml": {Data: []byte("extra")},
+	}
+
+	setup := func(t *testing.T) string {
+		dst := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dst, "app.yaml"), []byte("user"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return dst
+	}
+	read := func(t *testing.T, path string) string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	t.Run("overwrite", func(t *testing.T) {
+		dst := setup(t)
+		if err := ExtractTo(mem, "cfg", dst); err != nil {
+			t.Fatalf("ExtractTo error: %v", err)
+		}
+		if got := read(t, filepath.Join(dst, "app.yaml")); got != "default" {
+			t.Errorf("expected overwritten file, got %q", got)
+		}
+	})
+
+	t.Run("skip existing", func(t *testing.T) {
+		dst := setup(t)
+		if err := ExtractTo(mem, "cfg", dst, WithConflict(ConflictSkipExisting)); err != nil {
+			t.Fatalf("ExtractTo error: %v", err)
+		}
+		if got := read(t, filepath.Join(dst, "app.yaml")); got != "user" {
+			t.Errorf("expected user file kept, got %q", got)
+		}
+		if got := read(t, filepath.Join(dst, "extra.yaml")); got != "extra" {
+			t.Errorf("expected new file extracted, got %q", got)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		dst := setup(t)
+		err := ExtractTo(mem, "cfg", dst, WithConflict(ConflictError))
+		var de *DestError
+		if !errors.Is(err, ErrConflict) || !errors.As(err, &de) {
+			t.Fatalf("expected ErrConflict wrapped in *DestError, got %v", err)
+		}
+		if got := read(t, filepath.Join(dst, "app.yaml")); got != "user" {
+			t.Errorf("expected user file untouched, got %q", got)
+		}
+	})
+
+	t.Run("overwrite if different", func(t *testing.T) {
+		dst := setup(t)
+		same := filepath.Join(dst, "extra.yaml")
+		if err := os.WriteFile(same, []byte("extra"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		old := time.Now().Add(-time.Hour).Truncate(time.Second)
+		if err := os.Chtimes(same, old, old); err != nil {
+			t.Fatal(err)
+		}
+		var rep Report
+		if err := ExtractToDir(mem, "cfg", dst, ConflictOverwriteIfDifferent, WithReport(&rep)); err != nil {
+			t.Fatalf("ExtractToDir error: %v", err)
+		}
+		if got := read(t, filepath.Join(dst, "app.yaml")); got != "default" {
+			t.Errorf("expected the differing file overwritten, got %q", got)
+		}
+		if info, err := os.Stat(same); err != nil || !info.ModTime().Equal(old) {
+			t.Errorf("expected the identical file untouched, got %v (%v)", info, err)
+		}
+		if rep.Files != 1 || !slices.Equal(rep.Skipped, []string{"extra.yaml"}) {
+			t.Errorf("unexpected report %+v", rep)
+		}
+	})
+
+	t.Run("creates destination", func(t *testing.T) {
+		dst := filepath.Join(t.TempDir(), "new", "dir")
+		if err := ExtractTo(mem, "cfg", dst); err != nil {
+			t.Fatalf("ExtractTo error: %v", err)
+		}
+		if got := read(t, filepath.Join(dst, "extra.yaml")); got != "extra" {
+			t.Errorf("expected extra.yaml, got %q", got)
+		}
+	})
+}
+
+func TestExtractToRollback(t *testing.T) {
+	bad := badFS{base: fstest.MapFS{
+		"cfg/a.txt":      {Data: []byte("A")},
+		"cfg/keep/k.txt": {Data: []byte("K")},
+		"cfg/new/b.txt":  {Data: []byte("B")},
+		"cfg/z.txt":      {Data: []byte("Z")},
+	}, fail: "cfg/z.txt"}
+
+	t.Run("existing destination", func(t *testing.T) {
+		dst := t.TempDir()
+		if err := os.Mkdir(filepath.Join(dst, "keep"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dst, "user.txt"), []byte("user"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		var se *SourceError
+		if err := ExtractTo(bad, "cfg", dst, WithMetaFile()); !errors.As(err, &se) {
+			t.Fatalf("expected *SourceError, got %v", err)
+		}
+
+		entries, err := os.ReadDir(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		if len(names) != 2 || names[0] != "keep" || names[1] != "user.txt" {
+			t.Errorf("expected only pre-existing entries to remain, got %v", names)
+		}
+		if sub, err := os.ReadDir(filepath.Join(dst, "keep")); err != nil || len(sub) != 0 {
+			t.Errorf("expected pre-existing dir emptied of extracted files, got %v (err=%v)", sub, err)
+		}
+	})
+
+	t.Run("new destination", func(t *testing.T) {
+		parent := t.TempDir()
+		dst := filepath.Join(parent, "out", "sub")
+		if err := ExtractTo(bad, "cfg", dst); err == nil {
+			t.Fatal("expected error")
+		}
+		if _, err := os.Lstat(filepath.Join(parent, "out")); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected created destination removed, got err=%v", err)
+		}
+	})
+}
@@ -0,0 +1,338 @@
+package efs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Extraction is a handle to a directory extracted by Extract. It bundles the
+// directory with its cleanup and gives access to the extracted files and to
+// what the extraction did. All methods are safe for concurrent use.
+type Extraction struct {
+	dir     string
+	fsys    fs.FS
+	sources []source
+	entries []planEntry // What was extracted, for Watch
+	o       *options
+	bg      *background // Entries left for later by WithPriority; nil if none
+	report  Report      // Guarded by mu while bg runs
+	limits  softLimits  // Shared by the priority and background parts
+	prog    *progress   // Shared by the priority and background parts; nil without WithProgress
+	created time.Time   // When extraction started, for WithOnCleanup
+	shared  bool        // A WithContentName directory, left in place by Cleanup
+
+	mu           sync.Mutex
+	release      []func() error // Run in reverse order before removal, e.g. to drop locks
+	unlock       func() error   // The WithLock release, also in release; nil without a lock
+	done         bool           // Removed by Cleanup or handed over by MoveTo
+	cleanupErr   error
+	verifyFailed bool      // Verify reported a difference or failed, for WithOnCleanup
+	manifest     *Manifest // Written by WithManifestFile once extraction completed
+}
+
+// Report summarizes what an extraction materialized on disk.
+type Report struct {
+	Files    int           // Regular files written
+	Dirs     int           // Directories created below the extraction root
+	Bytes    int64         // Total bytes written to files
+	Symlinks int           // Symlinks and junctions created (see WithSymlinks)
+	Duration time.Duration // Wall-clock time spent extracting
+	Skipped  []string      // Entries deliberately not written, relative to the extraction root
+	Failed   []string      // Entries left out after an error under WithContinueOnError
+
+	DiskBytes int64 // Space the extraction root occupies on disk, as reported by DiskUsage
+	Inodes    int64 // Distinct inodes below and including the extraction root
+}
+
+// WithReport stores the Report of a successful extraction in r, for callers
+// of functions that return no Extraction, such as ExtractToTemp, ExtractTo
+// and Plan.Apply, who want to log or assert on what was materialized. For
+// ExtractTo and Plan.Apply, DiskBytes and Inodes are not measured, since the
+// destination may hold other content. With WithPriority, r covers the
+// priority entries only.
+func WithReport(r *Report) Option {
+	return func(o *options) { o.reportTo = r }
+}
+
+// Extract extracts the contents of root in fsys into a new temporary
+// directory and returns a handle to it. It is the handle-based form of
+// ExtractToTemp and takes the same parameters; see ExtractToTemp for the
+// details. Call Cleanup when the files are no longer needed.
+//
+// Example:
+//
+//	ex, err := efs.Extract(assets, "assets", "myassets", "")
+//	if err != nil { return err }
+//	defer ex.Cleanup()
+//	tmpl, err := template.ParseFiles(ex.Path("index.html"))
+func Extract(fsys fs.FS, root string, tempPrefix string, tempDir string, opts ...Option) (*Extraction, error) {
+	if root == "" {
+		root = "."
+	}
+	return extract(fsys, []source{{root: root, dest: "."}}, tempPrefix, tempDir, newOptions(opts))
+}
+
+// extract creates a new temporary directory and extracts sources into it.
+func extract(fsys fs.FS, sources []source, tempPrefix string, tempDir string, o *options) (_ *Extraction, err error) {
+	defer func() { o.reportError(err, "extract", false) }()
+	start := time.Now()
+
+	namePrefix := o.assignID(tempPrefix)
+	if err := validatePrefix(namePrefix); err != nil {
+		return nil, err
+	}
+
+	// Walk first, so source errors leave nothing behind on disk
+	entries := o.planned
+	if entries == nil {
+		if entries, err = prepare(fsys, sources, o); err != nil {
+			return nil, err
+		}
+	}
+	var digest string
+	if o.contentName {
+		if digest, err = o.contentDigest(fsys, entries); err != nil {
+			return nil, err
+		}
+	}
+	baseDir, err := o.execBase(o.tempBase(tempDir, planSize(entries)), o.hasExecutables(entries))
+	if err != nil {
+		return nil, err
+	}
+	if err := o.checkSpace(baseDir, planSize(entries)); err != nil {
+		return nil, err
+	}
+
+	// Create a temporary directory in the specified base directory, or in a
+	// fallback if that is unusable
+	var temp, final string
+	baseDir, err = o.createInBase(baseDir, tempDir, func(dir string) (err error) {
+		if dir, err = o.isolate(dir); err != nil {
+			return err
+		}
+		if digest == "" {
+			temp, err = o.mkdirTemp(dir, namePrefix)
+			return err
+		}
+		// WithContentName: reuse a complete directory, or stage a hidden
+		// sibling
+		final = filepath.Join(dir, namePrefix+"-"+digestKey(digest))
+		if completed(final, digest) {
+			temp = final
+			return nil
+		}
+		temp, err = os.MkdirTemp(dir, "."+namePrefix+"-")
+		return err
+	})
+	if err != nil {
+		return nil, &DestError{Path: baseDir, Err: fmt.Errorf("create temp dir: %w", err)}
+	}
+	absTempDir, absErr := filepath.Abs(temp)
+	if absErr != nil {
+		// Fallback to relative path if Abs fails
+		absTempDir = temp
+	}
+	if temp == final {
+		return reuseExtraction(fsys, sources, entries, absTempDir, o, start), nil
+	}
+
+	e := &Extraction{dir: absTempDir, fsys: fsys, sources: sources, entries: entries, o: o, created: start}
+	trackCreate(absTempDir)
+
+	if o.lock {
+		release, err := lockDir(absTempDir)
+		if err != nil {
+			e.Cleanup()
+			return nil, destErr(absTempDir, err)
+		}
+		e.release = append(e.release, release)
+		e.unlock = release
+	}
+
+	// The temp root stands in for the source root when its contents are
+	// extracted directly; otherwise it mirrors the top of fsys.
+	metaSrc := "."
+	if len(sources) == 1 && sources[0].dest == "." {
+		metaSrc = sources[0].root
+	}
+	if err := o.applyPrivateACL(absTempDir, true); err != nil {
+		e.Cleanup()
+		return nil, destErr(absTempDir, err)
+	}
+	if err := o.finish(fsys, metaSrc, absTempDir); err != nil {
+		e.Cleanup()
+		return nil, o.redactErr(destErr(absTempDir, fmt.Errorf("apply temp dir metadata: %w", err)), metaSrc)
+	}
+
+	if err := o.writeMeta(absTempDir, tempPrefix, sources); err != nil {
+		e.Cleanup()
+		return nil, destErr(absTempDir, err)
+	}
+	if err := o.writeNotices(fsys, entries, absTempDir); err != nil {
+		e.Cleanup()
+		return nil, err
+	}
+
+	first, rest := entries, []planEntry(nil)
+	if len(o.priority) > 0 && digest == "" {
+		first, rest = splitPriority(entries, o.priority)
+	}
+	e.prog = o.newProgress(entries)
+	a := &applier{fsys: fsys, o: o, rep: &e.report, limits: &e.limits, prog: e.prog}
+	err = a.apply(o.context(), first, absTempDir)
+	if o.immutable {
+		staged := absTempDir
+		e.release = append(e.release, func() error {
+			// publish may have moved the tree since the files were written
+			clearImmutable(rebase(a.written, staged, e.dir))
+			return nil
+		})
+	}
+	if err == nil && o.strictPerms {
+		err = CheckPermissions(absTempDir)
+	}
+	if err == nil && len(rest) == 0 {
+		e.manifest, err = o.writeManifest(absTempDir, entries)
+	}
+	if err != nil {
+		e.Cleanup() // Clean up if extraction fails
+		return nil, err
+	}
+	if final != "" {
+		if abs, err := filepath.Abs(final); err == nil {
+			final = abs
+		}
+		err := o.writeComplete(absTempDir, digest)
+		if err == nil {
+			err = e.publish(final, digest)
+		}
+		if err != nil {
+			e.Cleanup()
+			return nil, err
+		}
+		absTempDir = final
+	}
+
+	// Best effort: the tree is complete even if it cannot be measured
+	e.report.DiskBytes, e.report.Inodes, _ = DiskUsage(absTempDir)
+	e.report.Duration = time.Since(start)
+	if o.tracing() {
+		tracef("extract", o.display(absTempDir)+" id="+o.id, e.report.Duration, nil)
+	}
+	if o.reportTo != nil {
+		*o.reportTo = e.report
+	}
+	if len(rest) > 0 {
+		e.startBackground(rest, start)
+	}
+	return e, nil
+}
+
+// Dir returns the absolute path of the extracted directory.
+func (e *Extraction) Dir() string {
+	return e.dir
+}
+
+// Path returns the on-disk path of name, a slash-separated path relative to
+// the extraction root (e.g. "css/site.css").
+func (e *Extraction) Path(name string) string {
+	return filepath.Join(e.dir, filepath.FromSlash(name))
+}
+
+// Open opens the extracted file name for reading. name is slash-separated and
+// relative to the extraction root; paths escaping the root are rejected.
+func (e *Extraction) Open(name string) (*os.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return os.Open(e.Path(name))
+}
+
+// FS returns a read-only fs.FS view of the extracted directory, for APIs that
+// consume an fs.FS (template.ParseFS, http.FS) but should read the on-disk copy.
+// It is a DirFS rooted at Dir.
+func (e *Extraction) FS() fs.FS {
+	return DirFS(e.dir)
+}
+
+// Verify re-reads the source and checks that every extracted file still exists
+// with identical content and that no files were added. It returns nil for an
+// intact tree or a *VerifyError listing the differences.
+func (e *Extraction) Verify() (err error) {
+	defer func() {
+		if err != nil {
+			e.mu.Lock()
+			e.verifyFailed = true
+			e.mu.Unlock()
+		}
+	}()
+	if err := e.Wait(); err != nil {
+		return err
+	}
+	changes, err := verifyTree(e.fsys, e.sources, e.dir, e.o)
+	if err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		return &VerifyError{Changes: changes}
+	}
+	return nil
+}
+
+// Report returns a summary of what the extraction wrote. While a
+// WithPriority extraction continues in the background, it covers the entries
+// written so far in bulk: the priority entries first, the rest once Wait
+// would return.
+func (e *Extraction) Report() Report {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.report
+}
+
+// Cleanup removes the extracted directory. It is idempotent: only the first
+// call removes anything, and every call returns that first call's result.
+// After a successful MoveTo, Cleanup does nothing and returns nil; a
+// WithContentName directory is left in place.
+func (e *Extraction) Cleanup() error {
+	e.stopBackground()
+	e.mu.Lock()
+	if e.done {
+		defer e.mu.Unlock()
+		return e.cleanupErr
+	}
+	e.done = true
+	stats := CleanupStats{ID: e.o.id, Path: e.dir, Files: e.report.Files, VerifyFailed: e.verifyFailed}
+	if e.o.onCleanup != nil {
+		stats.DiskBytes, _, _ = DiskUsage(e.dir)
+	}
+	e.cleanupErr = e.releaseLocked()
+	if !e.shared {
+		e.cleanupErr = errors.Join(e.cleanupErr, e.o.traceOp("cleanup", e.dir, func() error { return os.RemoveAll(e.dir) }))
+	}
+	err := e.cleanupErr
+	e.mu.Unlock()
+
+	trackDone(e.dir)
+	e.o.reportError(err, "cleanup", false)
+	if e.o.onCleanup != nil {
+		stats.Alive, stats.Err = time.Since(e.created), err
+		e.o.onCleanup(stats)
+	}
+	return err
+}
+
+// releaseLocked runs the release functions in reverse order, once. e.mu must
+// be held.
+func (e *Extraction) releaseLocked() error {
+	var errs []error
+	for i := len(e.release) - 1; i >= 0; i-- {
+		errs = append(errs, e.release[i]())
+	}
+	e.release = nil
+	return errors.Join(errs...)
+}
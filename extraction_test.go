@@ -0,0 +1,118 @@
+package efs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExtractionHandle(t *testing.T) {
+	mem := fstest.MapFS{
+		"root/a.txt":     {Data: []byte("A")},
+		"root/sub/b.txt": {Data: []byte("BB")},
+	}
+
+	ex, err := Extract(mem, "root", "handle", t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	f, err := ex.Open("sub/b.txt")
+	if err != nil {
+		t.Fatalf("Open error: %v", err)
+	}
+	data, _ := io.ReadAll(f)
+	f.Close()
+	if string(data) != "BB" {
+		t.Errorf("expected %q, got %q", "BB", data)
+	}
+	if data, err := fs.ReadFile(ex.FS(), "sub/b.txt"); err != nil || string(data) != "BB" {
+		t.Errorf("expected FS view to serve sub/b.txt, got %q, %v", data, err)
+	}
+	if _, err := ex.Open("../escape"); err == nil {
+		t.Error("expected Open to reject paths outside the root")
+	}
+
+	rep := ex.Report()
+	if rep.Files != 2 || rep.Dirs != 1 || rep.Bytes != 3 || rep.Duration <= 0 {
+		t.Errorf("unexpected report %+v", rep)
+	}
+
+	if err := ex.Verify(); err != nil {
+		t.Fatalf("expected intact tree, got %v", err)
+	}
+	os.WriteFile(ex.Path("a.txt"), []byte("tampered"), 0o644)
+	var verr *VerifyError
+	if err := ex.Verify(); !errors.As(err, &verr) || verr.Changes[0] != (Change{Path: "a.txt", Kind: ChangeModified}) {
+		t.Fatalf("expected a.txt modified, got %v", err)
+	}
+
+	if err := ex.Cleanup(); err != nil {
+		t.Fatalf("Cleanup error: %v", err)
+	}
+	if err := ex.Cleanup(); err != nil {
+		t.Fatalf("second Cleanup error: %v", err)
+	}
+	if _, err := os.Stat(ex.Dir()); !os.IsNotExist(err) {
+		t.Fatalf("expected dir removed, got err=%v", err)
+	}
+}
+
+func TestWithOnFile(t *testing.T) {
+	mem := fstest.MapFS{
+		"root/a.txt":     {Data: []byte("A")},
+		"root/sub/b.txt": {Data: []byte("B")},
+	}
+
+	var got []string
+	e, err := Extract(mem, "root", "onfile", t.TempDir(), WithOnFile(func(rel, path string) {
+		// The file must be complete when reported.
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("reported file %s not on disk: %v", path, err)
+		}
+		got = append(got, rel)
+	}))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+
+	if !slices.Equal(got, []string{"a.txt", "sub/b.txt"}) {
+		t.Errorf("unexpected callbacks %v", got)
+	}
+}
+
+func TestWithReport(t *testing.T) {
+	mem := fstest.MapFS{
+		"assets/a.txt":   {Data: []byte("AA")},
+		"assets/b/c.txt": {Data: []byte("CCC")},
+	}
+
+	var rep Report
+	_, cleanup, err := ExtractToTemp(mem, "assets", "report", t.TempDir(), WithReport(&rep))
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+	if rep.Files != 2 || rep.Dirs != 1 || rep.Bytes != 5 || rep.Duration <= 0 || rep.DiskBytes <= 0 {
+		t.Errorf("unexpected report %+v", rep)
+	}
+
+	rep = Report{}
+	if err := ExtractTo(mem, "assets", t.TempDir(), WithReport(&rep)); err != nil {
+		t.Fatalf("ExtractTo error: %v", err)
+	}
+	if rep.Files != 2 || rep.Dirs != 1 || rep.Bytes != 5 || rep.Duration <= 0 {
+		t.Errorf("unexpected ExtractTo report %+v", rep)
+	}
+
+	rep = Report{Files: -1}
+	if _, _, err := ExtractToTemp(mem, "missing", "report", t.TempDir(), WithReport(&rep)); err == nil || rep.Files != -1 {
+		t.Errorf("expected a failed extraction to leave the report alone, got %+v (%v)", rep, err)
+	}
+}
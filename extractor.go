@@ -0,0 +1,71 @@
+package efs
+
+import (
+	"io/fs"
+	"slices"
+)
+
+// defaultPrefix is the temp name prefix of an Extractor without WithPrefix.
+const defaultPrefix = "efs"
+
+// WithPrefix sets the prefix of temporary directory and file names for an
+// Extractor; it must satisfy the rules for tempPrefix (see
+// ErrInvalidPrefix). Without it, an Extractor uses "efs". The package-level
+// functions ignore it in favor of their tempPrefix argument.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+		o.hasPrefix = true
+	}
+}
+
+// Extractor extracts with a fixed set of options, so that every setting,
+// including the temp name prefix (WithPrefix) and base directory
+// (WithTempDir), is an Option and new behavior can be added without
+// changing signatures. It is safe for concurrent use.
+//
+// Example:
+//
+//	x := efs.NewExtractor(efs.WithPrefix("myassets"), efs.WithFileMode(0o600),
+//		efs.WithConcurrency(8))
+//	ex, err := x.Extract(assets, "assets")
+//	if err != nil { return err }
+//	defer ex.Cleanup()
+type Extractor struct {
+	opts []Option
+}
+
+// NewExtractor returns an Extractor applying opts to every extraction.
+func NewExtractor(opts ...Option) *Extractor {
+	return &Extractor{opts: slices.Clone(opts)}
+}
+
+// options returns the Extractor options followed by opts, and the temp name
+// prefix they select.
+func (x *Extractor) options(opts []Option) ([]Option, string) {
+	all := append(slices.Clip(x.opts), opts...)
+	o := newOptions(all)
+	if !o.hasPrefix {
+		return all, defaultPrefix
+	}
+	return all, o.prefix
+}
+
+// Extract is Extract with the Extractor's options followed by opts.
+func (x *Extractor) Extract(fsys fs.FS, root string, opts ...Option) (*Extraction, error) {
+	all, prefix := x.options(opts)
+	return Extract(fsys, root, prefix, "", all...)
+}
+
+// ExtractToTemp is ExtractToTemp with the Extractor's options followed by
+// opts.
+func (x *Extractor) ExtractToTemp(fsys fs.FS, root string, opts ...Option) (string, func(), error) {
+	all, prefix := x.options(opts)
+	return ExtractToTemp(fsys, root, prefix, "", all...)
+}
+
+// ExtractFile is ExtractFile with the Extractor's options followed by opts.
+func (x *Extractor) ExtractFile(fsys fs.FS, filePath string, opts ...Option) (string, func(), error) {
+	all, prefix := x.options(opts)
+	return ExtractFile(fsys, filePath, prefix, "", all...)
+}
@@ -0,0 +1,49 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExtractor(t *testing.T) {
+	base := t.TempDir()
+	mem := fstest.MapFS{"assets/a.txt": {Data: []byte("A")}}
+
+	x := NewExtractor(WithTempDir(base), WithPrefix("myassets"), WithFileMode(0o600))
+	ex, err := x.Extract(mem, "assets")
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+	if filepath.Dir(ex.Dir()) != base || !strings.HasPrefix(filepath.Base(ex.Dir()), "myassets-") {
+		t.Errorf("unexpected directory %s", ex.Dir())
+	}
+
+	file, cleanup, err := x.ExtractFile(mem, "assets/a.txt", WithPrefix("cfg"))
+	if err != nil {
+		t.Fatalf("ExtractFile error: %v", err)
+	}
+	defer cleanup()
+	if !strings.HasPrefix(filepath.Base(file), "cfg-") {
+		t.Errorf("expected the per-call prefix, got %s", file)
+	}
+	if info, err := os.Stat(file); err != nil || info.Mode().Perm() != 0o600 && os.PathSeparator == '/' {
+		t.Errorf("expected mode 0600, got %v (%v)", info, err)
+	}
+
+	dir, cleanup, err := NewExtractor(WithTempDir(base)).ExtractToTemp(mem, "assets")
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+	if !strings.HasPrefix(filepath.Base(dir), defaultPrefix+"-") {
+		t.Errorf("expected the default prefix, got %s", dir)
+	}
+
+	if _, err := NewExtractor(WithPrefix("a/b")).Extract(mem, "assets"); err == nil {
+		t.Error("expected an invalid prefix error")
+	}
+}
@@ -0,0 +1,20 @@
+//go:build linux && (amd64 || arm64 || loong64 || mips64 || mips64le || ppc64 || ppc64le || riscv64 || s390x)
+
+package efs
+
+import (
+	"os"
+	"syscall"
+)
+
+const posixFadvSequential = 2 // POSIX_FADV_SEQUENTIAL
+
+// adviseSequential announces sequential access to the whole of f. Errors are
+// ignored; the advice is only a hint.
+func adviseSequential(f *os.File) {
+	if sc, err := f.SyscallConn(); err == nil {
+		_ = sc.Control(func(fd uintptr) {
+			syscall.Syscall6(syscall.SYS_FADVISE64, fd, 0, 0, posixFadvSequential, 0, 0)
+		})
+	}
+}
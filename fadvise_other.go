@@ -0,0 +1,8 @@
+//go:build !linux || !(amd64 || arm64 || loong64 || mips64 || mips64le || ppc64 || ppc64le || riscv64 || s390x)
+
+package efs
+
+import "os"
+
+// adviseSequential is a no-op where posix_fadvise is not wired up.
+func adviseSequential(f *os.File) {}
@@ -0,0 +1,101 @@
+package efs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+// BaseDirError is reported (wrapped in a *DestError) when the temporary
+// directory or file could not be created in the base directory nor in any
+// fallback. It lists every directory tried, in order, with its error.
+type BaseDirError struct {
+	Tried []string // Directories tried, primary base first
+	Errs  []error  // Errs[i] is why Tried[i] failed
+}
+
+func (e *BaseDirError) Error() string {
+	var b strings.Builder
+	b.WriteString("no writable base directory; tried ")
+	for i, dir := range e.Tried {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s (%v)", dir, e.Errs[i])
+	}
+	return b.String()
+}
+
+func (e *BaseDirError) Unwrap() []error { return e.Errs }
+
+// WithFallbackDirs adds base directories to try, in order, when the temporary
+// directory or file cannot be created in the resolved base directory because
+// it is read-only, not writable or missing, as on containers with a
+// read-only root file system. Pass a writable volume here. Without it,
+// extractions that use the default base directory still fall back to $TMPDIR
+// and /tmp; extractions with an explicit tempDir or WithTempDir fail as
+// before. If every candidate fails, the error is a *BaseDirError listing what
+// was tried.
+func WithFallbackDirs(dirs ...string) Option {
+	return func(o *options) { o.fallbackDirs = append(o.fallbackDirs, dirs...) }
+}
+
+// baseCandidates returns the directories to try after baseDir: the
+// WithFallbackDirs list, followed by the conventional temp locations when
+// the base directory was not chosen explicitly. Duplicates are dropped.
+func (o *options) baseCandidates(baseDir, tempDir string) []string {
+	candidates := []string{baseDir}
+	candidates = append(candidates, o.fallbackDirs...)
+	if tempDir == "" && o.tempDir == "" {
+		candidates = append(candidates, os.Getenv("TMPDIR"), os.TempDir())
+		if runtime.GOOS != "windows" {
+			candidates = append(candidates, "/tmp")
+		}
+	}
+	var out []string
+	for _, dir := range candidates {
+		if dir != "" && !slices.Contains(out, filepath.Clean(dir)) {
+			out = append(out, filepath.Clean(dir))
+		}
+	}
+	return out
+}
+
+// createInBase calls create for baseDir and, while it fails because the
+// directory is unusable, for each fallback candidate. It returns the
+// directory that worked.
+func (o *options) createInBase(baseDir, tempDir string, create func(dir string) error) (string, error) {
+	err := create(baseDir)
+	if err == nil || !unusableBase(err) {
+		return baseDir, err
+	}
+	candidates := o.baseCandidates(baseDir, tempDir)
+	if len(candidates) == 1 {
+		return baseDir, err
+	}
+	be := &BaseDirError{Tried: []string{baseDir}, Errs: []error{err}}
+	for _, dir := range candidates[1:] {
+		err := create(dir)
+		if err == nil {
+			return dir, nil
+		}
+		be.Tried = append(be.Tried, dir)
+		be.Errs = append(be.Errs, err)
+		if !unusableBase(err) {
+			break
+		}
+	}
+	return baseDir, be
+}
+
+// unusableBase reports whether err means a base directory cannot take new
+// entries at all, as opposed to a problem with the entry itself.
+func unusableBase(err error) bool {
+	return errors.Is(err, syscall.EROFS) || errors.Is(err, fs.ErrPermission) || errors.Is(err, fs.ErrNotExist)
+}
@@ -0,0 +1,62 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFallbackDirs(t *testing.T) {
+	mem := fstest.MapFS{"root/a.txt": {Data: []byte("A")}}
+	missing := filepath.Join(t.TempDir(), "missing")
+	volume := t.TempDir()
+
+	ex, err := Extract(mem, "root", "fallback", missing, WithFallbackDirs(volume))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+	if filepath.Dir(ex.Dir()) != volume {
+		t.Errorf("expected extraction in fallback %s, got %s", volume, ex.Dir())
+	}
+
+	file, cleanup, err := ExtractFile(mem, "root/a.txt", "fallback", missing, WithFallbackDirs(volume))
+	if err != nil {
+		t.Fatalf("ExtractFile error: %v", err)
+	}
+	defer cleanup()
+	if filepath.Dir(file) != volume {
+		t.Errorf("expected file in fallback %s, got %s", volume, file)
+	}
+
+	other := filepath.Join(volume, "also-missing")
+	_, err = Extract(mem, "root", "fallback", missing, WithFallbackDirs(other))
+	var be *BaseDirError
+	if !errors.As(err, &be) || !slices.Equal(be.Tried, []string{missing, other}) || !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected BaseDirError listing both candidates, got %v", err)
+	}
+
+	// An explicit base without fallbacks fails as before.
+	if _, err := Extract(mem, "root", "fallback", missing); errors.As(err, &be) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected plain not-exist error for explicit base, got %v", err)
+	}
+}
+
+func TestFallbackFromDefaultBase(t *testing.T) {
+	SetDefaultBaseDir(filepath.Join(t.TempDir(), "missing"))
+	defer SetDefaultBaseDir("")
+
+	mem := fstest.MapFS{"root/a.txt": {Data: []byte("A")}}
+	ex, err := Extract(mem, "root", "fallback", "")
+	if err != nil {
+		t.Fatalf("expected fallback to the system temp dir, got %v", err)
+	}
+	defer ex.Cleanup()
+	if filepath.Dir(ex.Dir()) != filepath.Clean(os.TempDir()) {
+		t.Errorf("expected extraction in %s, got %s", os.TempDir(), ex.Dir())
+	}
+}
@@ -0,0 +1,42 @@
+package efs
+
+import "io/fs"
+
+// WithInclude extracts only the regular files, symlinks and other non-directory
+// entries matching at least one of the glob patterns, e.g. "**/*.so" or
+// "bin/*". Patterns are matched against the path relative to the extraction
+// root like those of WithExecutables: a pattern without "/" matches the base
+// name at any depth, and a "**" segment matches any number of directories.
+// Directories are walked regardless, but those left without any included
+// entry below them are not created. Repeated calls add patterns.
+func WithInclude(globs ...string) Option {
+	return func(o *options) { o.include = append(o.include, globs...) }
+}
+
+// WithExclude leaves out entries matching any of the glob patterns, e.g.
+// "*.map"; an excluded directory is skipped with everything below it.
+// Patterns follow WithInclude. Exclusion wins over inclusion. Repeated calls
+// add patterns.
+func WithExclude(globs ...string) Option {
+	return func(o *options) { o.exclude = append(o.exclude, globs...) }
+}
+
+// WithFilter calls keep for every walked entry with its slash-separated path
+// relative to the extraction root, and leaves out the entry when it returns
+// false; for a directory, everything below it is skipped as well. It is
+// applied after WithInclude and WithExclude.
+func WithFilter(keep func(path string, d fs.DirEntry) bool) Option {
+	return func(o *options) { o.filter = keep }
+}
+
+// filtered reports whether the walked entry d at destination path rel is
+// left out by WithInclude, WithExclude or WithFilter.
+func (o *options) filtered(rel string, d fs.DirEntry) bool {
+	if matchAny(o.exclude, rel) {
+		return true
+	}
+	if len(o.include) > 0 && !d.IsDir() && !matchAny(o.include, rel) {
+		return true
+	}
+	return o.filter != nil && !o.filter(rel, d)
+}
@@ -0,0 +1,60 @@
+package efs
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFilters(t *testing.T) {
+	mem := fstest.MapFS{
+		"assets/lib/libfoo.so":     {Data: []byte("SO")},
+		"assets/lib/sub/libbar.so": {Data: []byte("SO")},
+		"assets/js/app.js":         {Data: []byte("JS")},
+		"assets/js/app.js.map":     {Data: []byte("MAP")},
+		"assets/docs/readme.txt":   {Data: []byte("TXT")},
+		"assets/lib/debug/x.so":    {Data: []byte("SO")},
+	}
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{"include", []Option{WithInclude("**/*.so")}, []string{"lib/debug/x.so", "lib/libfoo.so", "lib/sub/libbar.so"}},
+		{"exclude", []Option{WithExclude("*.map", "docs")}, []string{"js/app.js", "lib/debug/x.so", "lib/libfoo.so", "lib/sub/libbar.so"}},
+		{"both", []Option{WithInclude("*.so"), WithExclude("lib/debug")}, []string{"lib/libfoo.so", "lib/sub/libbar.so"}},
+		{"filter", []Option{WithFilter(func(p string, d fs.DirEntry) bool {
+			return d.IsDir() || path.Ext(p) != ".so"
+		})}, []string{"docs/readme.txt", "js/app.js", "js/app.js.map"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Extract(mem, "assets", "filter", t.TempDir(), tt.opts...)
+			if err != nil {
+				t.Fatalf("Extract error: %v", err)
+			}
+			defer e.Cleanup()
+			var got []string
+			fs.WalkDir(os.DirFS(e.Dir()), ".", func(p string, d fs.DirEntry, err error) error {
+				if err == nil && !d.IsDir() {
+					got = append(got, p)
+				}
+				return err
+			})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+			if tt.name == "include" {
+				if _, err := os.Stat(e.Path("docs")); err == nil {
+					t.Error("expected directories without included files to be left out")
+				}
+			}
+			if err := e.Verify(); err != nil {
+				t.Errorf("Verify error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package efs
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{".*", ".env", true},
+		{".*", "conf/.hidden", true},
+		{"*.sh", "bin/run.sh", true},
+		{"*.sh", "bin/run.shx", false},
+		{"bin/*", "bin/tool", true},
+		{"bin/*", "bin/sub/tool", false},
+		{"bin/**", "bin/sub/tool", true},
+		{"**/tool", "tool", true},
+		{"**/tool", "a/b/tool", true},
+		{"a/**/z", "a/z", true},
+		{"a/**/z", "a/b/c/z", true},
+		{"a/**/z", "a/b/c/y", false},
+		{"[", "x", false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
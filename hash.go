@@ -0,0 +1,119 @@
+package efs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc64"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Hash is a checksum algorithm for the digests efs records and compares, in
+// manifests and audit records. Digests are formatted "<Name>:<hex>". SHA-256
+// is the default; a fast non-cryptographic hash such as xxHash or BLAKE3
+// from a third-party package can be plugged in with NewHash when hashing
+// dominates the time spent fingerprinting multi-gigabyte trees and
+// tamper resistance is not needed.
+type Hash interface {
+	Name() string   // Digest prefix, e.g. "sha256"; must not contain ':'
+	New() hash.Hash // Returns a new hash.Hash computing the checksum
+}
+
+// Built-in hashes.
+var (
+	SHA256 = NewHash("sha256", sha256.New)
+	CRC64  = NewHash("crc64", func() hash.Hash { return crc64.New(crc64Table) }) // ECMA polynomial; fast, not tamper-resistant
+)
+
+var crc64Table = crc64.MakeTable(crc64.ECMA)
+
+// NewHash returns a Hash named name that computes checksums with fn, e.g.
+//
+//	efs.NewHash("xxh3", func() hash.Hash { return xxh3.New() })
+func NewHash(name string, fn func() hash.Hash) Hash {
+	return namedHash{name: name, fn: fn}
+}
+
+type namedHash struct {
+	name string
+	fn   func() hash.Hash
+}
+
+func (h namedHash) Name() string   { return h.name }
+func (h namedHash) New() hash.Hash { return h.fn() }
+
+// hashes maps digest prefixes to the hashes that produce them.
+var hashes = struct {
+	mu     sync.RWMutex
+	byName map[string]Hash
+}{byName: map[string]Hash{SHA256.Name(): SHA256, CRC64.Name(): CRC64}}
+
+// RegisterHash makes digests produced by h recognizable, so a Manifest
+// decoded from JSON whose digests use h can be checked. Hashes used with
+// WithHash in the same process are registered automatically. A later
+// registration under the same name replaces the earlier one.
+func RegisterHash(h Hash) {
+	hashes.mu.Lock()
+	defer hashes.mu.Unlock()
+	hashes.byName[h.Name()] = h
+}
+
+// hashFor returns the registered Hash that produced digest.
+func hashFor(digest string) (Hash, error) {
+	name, _, ok := strings.Cut(digest, ":")
+	if !ok {
+		return nil, fmt.Errorf("malformed digest %q", digest)
+	}
+	hashes.mu.RLock()
+	defer hashes.mu.RUnlock()
+	h, ok := hashes.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash %q in digest; see RegisterHash", name)
+	}
+	return h, nil
+}
+
+// WithHash selects the checksum algorithm for digests in audit records
+// (WithAuditLog) and manifests (NewManifest) instead of SHA-256.
+func WithHash(h Hash) Option {
+	return func(o *options) { o.hash = h }
+}
+
+// digestHash returns the WithHash algorithm, registering it, or SHA256.
+func (o *options) digestHash() Hash {
+	if o.hash == nil {
+		return SHA256
+	}
+	RegisterHash(o.hash)
+	return o.hash
+}
+
+// formatDigest formats the sum computed by h.
+func formatDigest(h Hash, sum []byte) string {
+	return h.Name() + ":" + hex.EncodeToString(sum)
+}
+
+// dataDigest returns the digest of data with h.
+func dataDigest(h Hash, data []byte) string {
+	hh := h.New()
+	hh.Write(data)
+	return formatDigest(h, hh.Sum(nil))
+}
+
+// fileDigest returns the digest of the file at path with h.
+func fileDigest(h Hash, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hh := h.New()
+	if _, err := io.Copy(hh, f); err != nil {
+		return "", err
+	}
+	return formatDigest(h, hh.Sum(nil)), nil
+}
@@ -0,0 +1,43 @@
+package efs
+
+import (
+	"hash"
+	"hash/fnv"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithHash(t *testing.T) {
+	mem := fstest.MapFS{"assets/a.txt": {Data: []byte("A")}}
+	ex, err := Extract(mem, "assets", "hash", t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	fnv64 := NewHash("fnv64a", func() hash.Hash { return fnv.New64a() })
+	for _, h := range []Hash{SHA256, CRC64, fnv64} {
+		m, err := NewManifest(ex.Dir(), WithHash(h))
+		if err != nil {
+			t.Fatalf("NewManifest(%s) error: %v", h.Name(), err)
+		}
+		if d := m.Files[0].Digest; !strings.HasPrefix(d, h.Name()+":") {
+			t.Errorf("expected a %s digest, got %s", h.Name(), d)
+		}
+		if changes, err := m.Check(ex.Dir()); err != nil || len(changes) != 0 {
+			t.Errorf("%s: expected no changes, got %v (%v)", h.Name(), changes, err)
+		}
+		os.WriteFile(ex.Path("a.txt"), []byte("B"), 0o644)
+		if changes, err := m.Check(ex.Dir()); err != nil || len(changes) != 1 {
+			t.Errorf("%s: expected the modification, got %v (%v)", h.Name(), changes, err)
+		}
+		os.WriteFile(ex.Path("a.txt"), []byte("A"), 0o644)
+	}
+
+	m := &Manifest{Files: []ManifestEntry{{Path: "a.txt", Size: 1, Digest: "blake3:00"}}}
+	if _, err := m.Check(ex.Dir()); err == nil || !strings.Contains(err.Error(), "RegisterHash") {
+		t.Errorf("expected an unknown hash error, got %v", err)
+	}
+}
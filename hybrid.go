@@ -0,0 +1,117 @@
+package efs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// hybridFS serves files from an extracted directory, falling back to the
+// source file system for names missing on disk.
+type hybridFS struct {
+	disk     fs.FS
+	fallback fs.FS
+}
+
+// Hybrid returns a read-only file system that serves each file from
+// extractedDir when it exists there and from fallback, typically the
+// embedded source, otherwise. Directory listings combine both, with the
+// on-disk entry winning for names present in both. A partially extracted
+// tree (see WithPriority) or one where users replaced or deleted some
+// bundled files thus behaves like a complete, customized asset set. Only a
+// missing name falls back; other errors on disk, such as a permission
+// problem, are returned.
+//
+// Example:
+//
+//	assets := efs.Hybrid(ex.Dir(), embedded)
+//	http.Handle("/", http.FileServerFS(assets))
+func Hybrid(extractedDir string, fallback fs.FS) fs.FS {
+	return hybridFS{disk: DirFS(extractedDir), fallback: fallback}
+}
+
+func (h hybridFS) Open(name string) (fs.File, error) {
+	f, err := h.disk.Open(name)
+	if errors.Is(err, fs.ErrNotExist) && fs.ValidPath(name) {
+		return h.fallback.Open(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info, err := f.Stat(); err != nil || !info.IsDir() {
+		return f, nil
+	}
+	// Directories present on disk list the fallback's entries too.
+	entries, err := h.ReadDir(name)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &hybridDir{File: f, entries: entries}, nil
+}
+
+// hybridDir is an open directory of a hybridFS, listing merged entries.
+type hybridDir struct {
+	fs.File
+	entries []fs.DirEntry
+}
+
+func (d *hybridDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+func (h hybridFS) ReadFile(name string) ([]byte, error) {
+	data, err := fs.ReadFile(h.disk, name)
+	if errors.Is(err, fs.ErrNotExist) && fs.ValidPath(name) {
+		return fs.ReadFile(h.fallback, name)
+	}
+	return data, err
+}
+
+func (h hybridFS) Stat(name string) (fs.FileInfo, error) {
+	info, err := fs.Stat(h.disk, name)
+	if errors.Is(err, fs.ErrNotExist) && fs.ValidPath(name) {
+		return fs.Stat(h.fallback, name)
+	}
+	return info, err
+}
+
+// ReadDir merges the listings of name on disk and in the fallback, sorted
+// by name.
+func (h hybridFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	onDisk, diskErr := fs.ReadDir(h.disk, name)
+	if diskErr != nil && !errors.Is(diskErr, fs.ErrNotExist) {
+		return nil, diskErr
+	}
+	if !fs.ValidPath(name) {
+		return nil, diskErr
+	}
+	fromSource, srcErr := fs.ReadDir(h.fallback, name)
+	switch {
+	case srcErr != nil && diskErr != nil:
+		return nil, srcErr
+	case srcErr != nil && !errors.Is(srcErr, fs.ErrNotExist):
+		return nil, srcErr
+	}
+	entries := onDisk
+	for _, e := range fromSource {
+		if !slices.ContainsFunc(onDisk, func(d fs.DirEntry) bool { return d.Name() == e.Name() }) {
+			entries = append(entries, e)
+		}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
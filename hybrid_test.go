@@ -0,0 +1,52 @@
+package efs
+
+import (
+	"io/fs"
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHybrid(t *testing.T) {
+	src := fstest.MapFS{
+		"assets/a.txt":     {Data: []byte("A")},
+		"assets/sub/b.txt": {Data: []byte("B")},
+	}
+	ex, err := Extract(src, "assets", "hybrid", t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	// The user customized a.txt, added c.txt and lost sub/b.txt.
+	os.WriteFile(ex.Path("a.txt"), []byte("custom"), 0o644)
+	os.WriteFile(ex.Path("c.txt"), []byte("C"), 0o644)
+	os.Remove(ex.Path("sub/b.txt"))
+
+	sub, _ := fs.Sub(src, "assets")
+	h := Hybrid(ex.Dir(), sub)
+	for name, want := range map[string]string{"a.txt": "custom", "c.txt": "C", "sub/b.txt": "B"} {
+		if data, err := fs.ReadFile(h, name); err != nil || string(data) != want {
+			t.Errorf("ReadFile(%s): expected %q, got %q (%v)", name, want, data, err)
+		}
+	}
+
+	var files []string
+	err = fs.WalkDir(h, ".", func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, p)
+		}
+		return err
+	})
+	if err != nil || !slices.Equal(files, []string{"a.txt", "c.txt", "sub/b.txt"}) {
+		t.Errorf("expected the merged tree, got %v (%v)", files, err)
+	}
+
+	if _, err := h.Open("missing.txt"); !os.IsNotExist(err) {
+		t.Errorf("expected ErrNotExist, got %v", err)
+	}
+	if err := fstest.TestFS(h, "a.txt", "c.txt", "sub/b.txt"); err != nil {
+		t.Errorf("TestFS: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package efs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// WithExtractionID sets the ID of the extraction, typically a request or job
+// ID from the caller's logs. Without it, Extract generates a random
+// 16-character hex ID. The ID is reported by Extraction.ID and recorded in
+// the WithMetaFile marker and in WithAuditLog records, so operators can
+// correlate a directory found on disk with the request that created it; see
+// WithIDInName to put it into the directory name as well.
+func WithExtractionID(id string) Option {
+	return func(o *options) { o.id = id }
+}
+
+// WithIDInName inserts the extraction ID into the name of the temporary
+// directory or file, between the tempPrefix and the random suffix (e.g.
+// "myassets-<id>-123456"). The ID must then satisfy the same rules as the
+// prefix (see ErrInvalidPrefix).
+func WithIDInName() Option {
+	return func(o *options) { o.idInName = true }
+}
+
+// newID returns a random extraction ID.
+func newID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// assignID generates an extraction ID unless one was given and returns the
+// temp name prefix to use for tempPrefix.
+func (o *options) assignID(tempPrefix string) string {
+	if o.id == "" {
+		o.id = newID()
+	}
+	if o.idInName {
+		return tempPrefix + "-" + o.id
+	}
+	return tempPrefix
+}
+
+// ID returns the extraction ID set with WithExtractionID or generated for
+// this extraction.
+func (e *Extraction) ID() string {
+	return e.o.id
+}
@@ -0,0 +1,65 @@
+package efs
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExtractionID(t *testing.T) {
+	mem := fstest.MapFS{"assets/a.txt": {Data: []byte("A")}}
+
+	var audit bytes.Buffer
+	ex, err := Extract(mem, "assets", "job", t.TempDir(),
+		WithExtractionID("req-42"), WithIDInName(), WithMetaFile(), WithAuditLog(&audit))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	if ex.ID() != "req-42" {
+		t.Errorf("expected ID req-42, got %q", ex.ID())
+	}
+	if name := filepath.Base(ex.Dir()); !strings.HasPrefix(name, "job-req-42-") {
+		t.Errorf("expected the ID in the directory name, got %s", name)
+	}
+	m, err := ReadMeta(ex.Dir())
+	if err != nil {
+		t.Fatalf("ReadMeta error: %v", err)
+	}
+	if m.ID != "req-42" || m.Prefix != "job" {
+		t.Errorf("unexpected meta %+v", m)
+	}
+	if !strings.Contains(audit.String(), `"id":"req-42"`) {
+		t.Errorf("audit record lacks the ID: %s", audit.String())
+	}
+}
+
+func TestExtractionIDGenerated(t *testing.T) {
+	mem := fstest.MapFS{"assets/a.txt": {Data: []byte("A")}}
+	base := t.TempDir()
+
+	a, err := Extract(mem, "assets", "job", base)
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer a.Cleanup()
+	b, err := Extract(mem, "assets", "job", base)
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer b.Cleanup()
+
+	if len(a.ID()) != 16 || a.ID() == b.ID() {
+		t.Errorf("expected distinct generated IDs, got %q and %q", a.ID(), b.ID())
+	}
+	if strings.Contains(a.Dir(), a.ID()) {
+		t.Errorf("ID in directory name without WithIDInName: %s", a.Dir())
+	}
+
+	if _, err := Extract(mem, "assets", "job", base, WithExtractionID("a/b"), WithIDInName()); err == nil {
+		t.Error("expected an ID with a path separator to be rejected in the name")
+	}
+}
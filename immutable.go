@@ -0,0 +1,65 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+)
+
+// WithImmutable marks every extracted file immutable (chattr +i on Linux,
+// uchg on macOS and the BSDs) once extraction has succeeded, so not even the
+// owner can modify or delete bundled rulesets. Cleanup clears the flag again
+// before removing the files; after ExtractTo use ClearImmutable. Setting the
+// flag needs CAP_LINUX_IMMUTABLE on Linux and a file system that supports it;
+// otherwise the extraction fails. It is not supported on Windows.
+func WithImmutable() Option {
+	return func(o *options) { o.immutable = true }
+}
+
+// ClearImmutable removes the immutable flag set by WithImmutable from every
+// regular file below dir, so the tree can be modified or removed again.
+func ClearImmutable(dir string) error {
+	var errs []error
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		errs = append(errs, setImmutable(path, false))
+		return nil
+	})
+	return errors.Join(append(errs, err)...)
+}
+
+// makeImmutable sets the immutable flag on paths. If that fails part way,
+// the flags already set are cleared again.
+func makeImmutable(paths []string) error {
+	for i, p := range paths {
+		if err := setImmutable(p, true); err != nil {
+			clearImmutable(paths[:i])
+			return destErr(p, err)
+		}
+	}
+	return nil
+}
+
+// rebase returns paths below the directory from moved to the directory to.
+func rebase(paths []string, from, to string) []string {
+	if from == to {
+		return paths
+	}
+	moved := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if rel, err := filepath.Rel(from, p); err == nil {
+			p = filepath.Join(to, rel)
+		}
+		moved = append(moved, p)
+	}
+	return moved
+}
+
+// clearImmutable clears the immutable flag on paths, ignoring errors.
+func clearImmutable(paths []string) {
+	for _, p := range paths {
+		_ = setImmutable(p, false)
+	}
+}
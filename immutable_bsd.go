@@ -0,0 +1,28 @@
+//go:build darwin || freebsd || netbsd || openbsd || dragonfly
+
+package efs
+
+import (
+	"os"
+	"syscall"
+)
+
+const ufImmutable = 0x2 // UF_IMMUTABLE ("uchg")
+
+// setImmutable sets or clears the user immutable flag on the file at path.
+func setImmutable(path string, on bool) error {
+	var st syscall.Stat_t
+	if err := syscall.Lstat(path, &st); err != nil {
+		return &os.PathError{Op: "lstat", Path: path, Err: err}
+	}
+	flags := int(st.Flags)
+	if on {
+		flags |= ufImmutable
+	} else {
+		flags &^= ufImmutable
+	}
+	if err := syscall.Chflags(path, flags); err != nil {
+		return &os.PathError{Op: "chflags", Path: path, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package efs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithImmutable(t *testing.T) {
+	mem := fstest.MapFS{"rules/a.rules": {Data: []byte("deny all")}}
+	e, err := Extract(mem, "rules", "immutable", t.TempDir(), WithImmutable())
+	if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EOPNOTSUPP) {
+		t.Skipf("immutable flag not available: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+
+	if err := os.WriteFile(e.Path("a.rules"), []byte("allow all"), 0o644); !errors.Is(err, syscall.EPERM) {
+		t.Errorf("expected EPERM writing an immutable file, got %v", err)
+	}
+	if err := e.Cleanup(); err != nil {
+		t.Fatalf("Cleanup error: %v", err)
+	}
+	if _, err := os.Stat(e.Dir()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected directory removed, got %v", err)
+	}
+}
+
+func TestImmutableContentName(t *testing.T) {
+	mem := fstest.MapFS{"a.rules": {Data: []byte("deny all")}}
+	e, err := Extract(mem, ".", "immutable", t.TempDir(), WithImmutable(), WithContentName())
+	if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EOPNOTSUPP) {
+		t.Skipf("immutable flag not available: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+
+	// The files were made immutable in the staging directory; Cleanup must
+	// find them at the published location.
+	if err := e.Cleanup(); err != nil {
+		t.Fatalf("Cleanup error: %v", err)
+	}
+	if err := os.WriteFile(e.Path("a.rules"), []byte("allow all"), 0o644); err != nil {
+		t.Errorf("expected the immutable flag cleared after Cleanup, got %v", err)
+	}
+}
+
+func TestImmutableExtractCached(t *testing.T) {
+	mem := fstest.MapFS{"a.rules": {Data: []byte("deny all")}}
+	dir, err := ExtractCached(mem, ".", "immutable", WithCacheDir(t.TempDir()), WithImmutable())
+	if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EOPNOTSUPP) {
+		t.Skipf("immutable flag not available: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("ExtractCached error: %v", err)
+	}
+	path := filepath.Join(dir, "a.rules")
+	defer clearImmutable([]string{path})
+
+	// The cache is never cleaned up, so its files stay immutable.
+	if err := os.WriteFile(path, []byte("allow all"), 0o644); !errors.Is(err, syscall.EPERM) {
+		t.Errorf("expected EPERM writing a cached immutable file, got %v", err)
+	}
+}
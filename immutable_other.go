@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package efs
+
+import (
+	"errors"
+	"os"
+)
+
+func setImmutable(path string, on bool) error {
+	return &os.PathError{Op: "set immutable", Path: path, Err: errors.ErrUnsupported}
+}
@@ -0,0 +1,9 @@
+//go:build linux && !(mips || mipsle || mips64 || mips64le || ppc64 || ppc64le)
+
+package efs
+
+// Direction bits of ioctl request numbers (asm-generic).
+const (
+	iocWrite uintptr = 1 << 30
+	iocRead  uintptr = 2 << 30
+)
@@ -0,0 +1,9 @@
+//go:build linux && (mips || mipsle || mips64 || mips64le || ppc64 || ppc64le)
+
+package efs
+
+// Direction bits of ioctl request numbers on MIPS and PowerPC.
+const (
+	iocRead  uintptr = 2 << 29
+	iocWrite uintptr = 4 << 29
+)
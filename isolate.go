@@ -0,0 +1,36 @@
+package efs
+
+import "errors"
+
+// ErrUnsafeBaseDir is reported (wrapped in a *DestError) when the per-user
+// directory of WithUserIsolation exists but is not a directory owned by the
+// current user and private to it, which suggests another local user planted
+// it.
+var ErrUnsafeBaseDir = errors.New("per-user base directory is not private to the current user")
+
+// WithUserIsolation creates temporary entries in a per-user subdirectory
+// "efs-<uid>" of the base directory instead of the base directory itself.
+// The subdirectory is created with mode 0700 and, when it already exists,
+// reused only if it is a real directory (not a symlink) owned by the current
+// user without group or other permissions. This keeps other local users on
+// shared locations such as /tmp from squatting on or redirecting predictable
+// extraction paths. The subdirectory is left in place by Cleanup for reuse.
+// On Windows, where the temp directory is already per-user, and on platforms
+// without user IDs, it has no effect.
+func WithUserIsolation() Option {
+	return func(o *options) { o.userIsolation = true }
+}
+
+// isolate returns the directory in base where temporary entries go: the
+// verified per-user subdirectory with WithUserIsolation, else base itself,
+// and within that the WithNamespace directory if any.
+func (o *options) isolate(base string) (string, error) {
+	dir := base
+	if o.userIsolation {
+		var err error
+		if dir, err = userDir(base); err != nil {
+			return "", err
+		}
+	}
+	return o.namespaceDir(dir)
+}
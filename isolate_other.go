@@ -0,0 +1,9 @@
+//go:build !unix
+
+package efs
+
+// userDir returns base unchanged: temp directories are per-user already on
+// Windows, and other platforms have no user IDs to isolate by.
+func userDir(base string) (string, error) {
+	return base, nil
+}
@@ -0,0 +1,36 @@
+//go:build unix
+
+package efs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+)
+
+// userDir creates or verifies the private per-user directory in base.
+func userDir(base string) (string, error) {
+	uid := os.Getuid()
+	dir := filepath.Join(base, "efs-"+strconv.Itoa(uid))
+	if err := os.Mkdir(dir, 0o700); err != nil && !errors.Is(err, fs.ErrExist) {
+		return "", err
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	switch {
+	case !info.IsDir():
+		return "", fmt.Errorf("%w: %s is not a directory", ErrUnsafeBaseDir, dir)
+	case ok && int(st.Uid) != uid:
+		return "", fmt.Errorf("%w: %s is owned by uid %d", ErrUnsafeBaseDir, dir, st.Uid)
+	case info.Mode().Perm()&0o077 != 0:
+		return "", fmt.Errorf("%w: %s has mode %v", ErrUnsafeBaseDir, dir, info.Mode().Perm())
+	}
+	return dir, nil
+}
@@ -0,0 +1,52 @@
+//go:build unix
+
+package efs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"testing/fstest"
+)
+
+func TestUserIsolation(t *testing.T) {
+	mem := fstest.MapFS{"root/a.txt": {Data: []byte("A")}}
+	base := t.TempDir()
+	private := filepath.Join(base, "efs-"+strconv.Itoa(os.Getuid()))
+
+	for range 2 { // Created first, reused second
+		ex, err := Extract(mem, "root", "iso", base, WithUserIsolation())
+		if err != nil {
+			t.Fatalf("Extract error: %v", err)
+		}
+		if filepath.Dir(ex.Dir()) != private {
+			t.Errorf("expected extraction in %s, got %s", private, ex.Dir())
+		}
+		ex.Cleanup()
+	}
+	if info, err := os.Lstat(private); err != nil || info.Mode().Perm() != 0o700 {
+		t.Fatalf("expected private dir with mode 0700, got %v", err)
+	}
+
+	os.Chmod(private, 0o755)
+	if _, err := Extract(mem, "root", "iso", base, WithUserIsolation()); !errors.Is(err, ErrUnsafeBaseDir) {
+		t.Errorf("expected ErrUnsafeBaseDir for a group-readable dir, got %v", err)
+	}
+
+	os.Remove(private)
+	os.Symlink(t.TempDir(), private) // A squatter redirecting the path
+	if _, _, err := ExtractFile(mem, "root/a.txt", "iso", base, WithUserIsolation()); !errors.Is(err, ErrUnsafeBaseDir) {
+		t.Errorf("expected ErrUnsafeBaseDir for a symlink, got %v", err)
+	}
+
+	if os.Getuid() == 0 {
+		os.Remove(private)
+		os.Mkdir(private, 0o700)
+		os.Chown(private, 12345, 12345)
+		if _, err := Extract(mem, "root", "iso", base, WithUserIsolation()); !errors.Is(err, ErrUnsafeBaseDir) {
+			t.Errorf("expected ErrUnsafeBaseDir for a foreign owner, got %v", err)
+		}
+	}
+}
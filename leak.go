@@ -0,0 +1,88 @@
+package efs
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Leak describes an extraction whose cleanup has not been called.
+type Leak struct {
+	Path    string    // Extracted directory or file
+	Created time.Time // When the extraction was created
+	Stack   string    // Stack trace of the call that created it
+}
+
+// leaks holds the live extractions while tracking is enabled.
+var leaks struct {
+	mu   sync.Mutex
+	on   bool
+	live map[string]Leak
+}
+
+// TrackLeaks turns leak tracking on or off for the whole process. While it is
+// on, every extraction records its path and the stack trace of the call that
+// created it until its cleanup runs (or MoveTo hands it over), so that Leaks
+// and ReportLeaks can point at the call sites of long-running services that
+// leak temp directories. Recording a stack trace costs a few microseconds per
+// extraction. Turning tracking off forgets all recorded extractions.
+//
+// Go has no exit hooks, so report from main before returning, typically with
+//
+//	efs.TrackLeaks(true)
+//	defer efs.ReportLeaks(os.Stderr)
+func TrackLeaks(on bool) {
+	leaks.mu.Lock()
+	defer leaks.mu.Unlock()
+	leaks.on = on
+	if !on {
+		leaks.live = nil
+	}
+}
+
+// Leaks returns the tracked extractions whose cleanup has not been called,
+// oldest first. It returns nil unless TrackLeaks is on.
+func Leaks() []Leak {
+	leaks.mu.Lock()
+	defer leaks.mu.Unlock()
+	var list []Leak
+	for _, l := range leaks.live {
+		list = append(list, l)
+	}
+	slices.SortFunc(list, func(a, b Leak) int { return a.Created.Compare(b.Created) })
+	return list
+}
+
+// ReportLeaks writes one entry per leaked extraction, with its path, age and
+// creation stack trace, to w and returns the number of leaks.
+func ReportLeaks(w io.Writer) int {
+	list := Leaks()
+	for _, l := range list {
+		fmt.Fprintf(w, "efs: leaked %s (created %s ago, cleanup never called)\n%s\n",
+			l.Path, time.Since(l.Created).Round(time.Millisecond), l.Stack)
+	}
+	return len(list)
+}
+
+// trackCreate records path as a live extraction if tracking is on.
+func trackCreate(path string) {
+	leaks.mu.Lock()
+	defer leaks.mu.Unlock()
+	if !leaks.on {
+		return
+	}
+	if leaks.live == nil {
+		leaks.live = make(map[string]Leak)
+	}
+	leaks.live[path] = Leak{Path: path, Created: time.Now(), Stack: string(debug.Stack())}
+}
+
+// trackDone forgets the extraction at path.
+func trackDone(path string) {
+	leaks.mu.Lock()
+	defer leaks.mu.Unlock()
+	delete(leaks.live, path)
+}
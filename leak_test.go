@@ -0,0 +1,43 @@
+package efs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTrackLeaks(t *testing.T) {
+	TrackLeaks(true)
+	defer TrackLeaks(false)
+	base := t.TempDir()
+	mem := fstest.MapFS{"assets/a.txt": {Data: []byte("A")}}
+
+	ex, err := Extract(mem, "assets", "leak", base)
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	file, cleanupFile, err := ExtractFile(mem, "assets/a.txt", "leak", base)
+	if err != nil {
+		t.Fatalf("ExtractFile error: %v", err)
+	}
+
+	leaked := Leaks()
+	if len(leaked) != 2 || leaked[0].Path != ex.Dir() || leaked[1].Path != file {
+		t.Fatalf("expected both extractions as leaks, got %+v", leaked)
+	}
+	if !strings.Contains(leaked[0].Stack, "TestTrackLeaks") {
+		t.Errorf("expected the creating call site in the stack, got:\n%s", leaked[0].Stack)
+	}
+
+	ex.Cleanup()
+	var buf bytes.Buffer
+	if n := ReportLeaks(&buf); n != 1 || !strings.Contains(buf.String(), file) {
+		t.Errorf("expected one reported leak for %s, got %d:\n%s", file, n, buf.String())
+	}
+
+	cleanupFile()
+	if leaked := Leaks(); len(leaked) != 0 {
+		t.Errorf("expected no leaks after cleanup, got %+v", leaked)
+	}
+}
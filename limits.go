@@ -0,0 +1,47 @@
+package efs
+
+// LimitWarning reports that an extraction crossed a threshold set with
+// WithSoftLimits.
+type LimitWarning struct {
+	Limit     string // "bytes" or "files"
+	Threshold int64  // The configured threshold
+	Value     int64  // Bytes or files written when it was crossed
+	Path      string // File whose extraction crossed it, relative to the extraction root
+}
+
+// WithSoftLimits calls warn when an extraction writes more than bytes bytes
+// or more than files files in total, but keeps extracting, so capacity
+// problems surface in logs or metrics before they turn into failures such as
+// a full disk. Each threshold is reported at most once per extraction (per
+// Apply for a Plan); a zero threshold is not checked. warn runs on the
+// extracting goroutine and should not block.
+func WithSoftLimits(bytes int64, files int, warn func(LimitWarning)) Option {
+	return func(o *options) {
+		o.softBytes, o.softFiles = bytes, int64(files)
+		o.softWarn = warn
+	}
+}
+
+// softLimits tracks the totals of one extraction against WithSoftLimits.
+type softLimits struct {
+	bytes, files             int64
+	warnedBytes, warnedFiles bool
+}
+
+// add counts a file of size bytes written at rel and warns about thresholds
+// crossed by it.
+func (s *softLimits) add(o *options, rel string, size int64) {
+	if s == nil || o.softWarn == nil {
+		return
+	}
+	s.bytes += size
+	s.files++
+	if o.softBytes > 0 && s.bytes > o.softBytes && !s.warnedBytes {
+		s.warnedBytes = true
+		o.softWarn(LimitWarning{Limit: "bytes", Threshold: o.softBytes, Value: s.bytes, Path: rel})
+	}
+	if o.softFiles > 0 && s.files > o.softFiles && !s.warnedFiles {
+		s.warnedFiles = true
+		o.softWarn(LimitWarning{Limit: "files", Threshold: o.softFiles, Value: s.files, Path: rel})
+	}
+}
@@ -0,0 +1,38 @@
+package efs
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithSoftLimits(t *testing.T) {
+	mem := fstest.MapFS{
+		"assets/a.txt": {Data: []byte("AAAA")},
+		"assets/b.txt": {Data: []byte("BBBB")},
+		"assets/c.txt": {Data: []byte("CCCC")},
+	}
+
+	var warnings []LimitWarning
+	warn := func(w LimitWarning) { warnings = append(warnings, w) }
+	ex, err := Extract(mem, "assets", "soft", t.TempDir(), WithOrdered(), WithSoftLimits(6, 2, warn))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	want := []LimitWarning{
+		{Limit: "bytes", Threshold: 6, Value: 8, Path: "b.txt"},
+		{Limit: "files", Threshold: 2, Value: 3, Path: "c.txt"},
+	}
+	if len(warnings) != len(want) {
+		t.Fatalf("expected %d warnings, got %+v", len(want), warnings)
+	}
+	for i := range want {
+		if warnings[i] != want[i] {
+			t.Errorf("warning %d: expected %+v, got %+v", i, want[i], warnings[i])
+		}
+	}
+	if rep := ex.Report(); rep.Files != 3 {
+		t.Errorf("expected extraction to go on past the limits, got %+v", rep)
+	}
+}
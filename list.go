@@ -0,0 +1,59 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"iter"
+)
+
+// errStopList ends a List walk when the consumer stops ranging.
+var errStopList = errors.New("efs: list stopped")
+
+// List returns an iterator over the entries ExtractToTemp would materialize
+// for the same fsys, root and options, without writing anything. Each entry
+// is yielded with its slash-separated destination path relative to the
+// extraction root, in extraction order. Use it to preview, count or select
+// entries before committing to disk writes:
+//
+//	entries, walkErr := efs.List(assets, "assets")
+//	for name, d := range entries {
+//		fmt.Println(name, d.IsDir())
+//	}
+//	if err := walkErr(); err != nil { return err }
+//
+// If walking the source fails, the iteration ends early and the second
+// result, called after ranging, returns the error as a *SourceError; it
+// returns nil after a complete walk or one the consumer stopped.
+func List(fsys fs.FS, root string, opts ...Option) (iter.Seq2[string, fs.DirEntry], func() error) {
+	if root == "" {
+		root = "."
+	}
+	o := newOptions(opts)
+	src := source{root: root, dest: "."}
+	var walkErr error
+	errFn := func() error { return walkErr }
+	if o.ordered || o.skipEmptyDirs {
+		// These options need the whole tree before the first entry is known.
+		return func(yield func(string, fs.DirEntry) bool) {
+			entries, err := prepare(fsys, []source{src}, o)
+			walkErr = err
+			for _, e := range entries {
+				if !yield(e.rel, e.d) {
+					return
+				}
+			}
+		}, errFn
+	}
+	return func(yield func(string, fs.DirEntry) bool) {
+		err := walkSource(fsys, src, o, func(p, rel string, d fs.DirEntry) error {
+			if !yield(rel, d) {
+				return errStopList
+			}
+			return nil
+		})
+		if errors.Is(err, errStopList) {
+			err = nil
+		}
+		walkErr = err
+	}, errFn
+}
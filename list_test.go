@@ -0,0 +1,75 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestList(t *testing.T) {
+	mem := fstest.MapFS{
+		"root/a.txt":     {Data: []byte("A")},
+		"root/sub/b.txt": {Data: []byte("B")},
+		"other.txt":      {Data: []byte("O")},
+	}
+
+	var names []string
+	entries, walkErr := List(mem, "root")
+	for name := range entries {
+		names = append(names, name)
+	}
+	want := []string{"a.txt", "sub", "sub/b.txt"}
+	if !slices.Equal(names, want) || walkErr() != nil {
+		t.Errorf("expected %v, got %v, %v", want, names, walkErr())
+	}
+
+	// Breaking out of the loop must stop the walk cleanly.
+	count := 0
+	for range entries {
+		count++
+		break
+	}
+	if count != 1 || walkErr() != nil {
+		t.Errorf("expected a single iteration without error, got %d, %v", count, walkErr())
+	}
+
+	// A failing walk is reported, not mistaken for a complete listing.
+	for _, opts := range [][]Option{nil, {WithOrdered()}} {
+		entries, walkErr := List(badFS{base: mem, fail: "root/sub"}, "root", opts...)
+		for range entries {
+		}
+		if se := (*SourceError)(nil); !errors.As(walkErr(), &se) {
+			t.Errorf("expected a *SourceError, got %v", walkErr())
+		}
+	}
+}
+
+func TestSkipEmptyDirs(t *testing.T) {
+	mem := fstest.MapFS{
+		"root/a/b/file.txt": {Data: []byte("F")},
+		"root/empty":        {Mode: fs.ModeDir},
+		"root/nested/deep":  {Mode: fs.ModeDir},
+	}
+
+	dir, cleanup, err := ExtractToTemp(mem, "root", "empty", t.TempDir())
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+	if info, err := os.Stat(filepath.Join(dir, "nested", "deep")); err != nil || !info.IsDir() {
+		t.Errorf("expected empty directory recreated by default, got %v", err)
+	}
+
+	var names []string
+	entries, _ := List(mem, "root", WithSkipEmptyDirs())
+	for name := range entries {
+		names = append(names, name)
+	}
+	if want := []string{"a", "a/b", "a/b/file.txt"}; !slices.Equal(names, want) {
+		t.Errorf("expected %v, got %v", want, names)
+	}
+}
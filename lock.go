@@ -0,0 +1,53 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// LockFileName is the file in an extraction root that WithLock holds locked.
+const LockFileName = ".efs-lock"
+
+// WithLock takes an exclusive advisory lock (flock, or LockFileEx on Windows)
+// on a LockFileName file in the extraction root for the lifetime of the
+// handle, so other processes such as cleanup tools or sibling instances can
+// tell with IsLocked that the directory is in use. The lock is taken before
+// any content is extracted and released by Cleanup. It applies to temp
+// extractions (Extract, ExtractToTemp); on platforms without file locking the
+// lock file is still created.
+func WithLock() Option {
+	return func(o *options) { o.lock = true }
+}
+
+// IsLocked reports whether the extraction root dir is held by WithLock in any
+// process. A directory without lock file is not locked. Where file locking is
+// unavailable, the presence of the lock file counts as locked.
+func IsLocked(dir string) bool {
+	f, err := os.Open(filepath.Join(dir, LockFileName))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	if err := lockFile(f); err != nil {
+		return true
+	}
+	_ = unlockFile(f)
+	return !lockSupported
+}
+
+// lockDir creates and locks the lock file in dir and returns a function that
+// releases it.
+func lockDir(dir string) (release func() error, err error) {
+	f, err := os.OpenFile(filepath.Join(dir, LockFileName), os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, &os.PathError{Op: "lock", Path: f.Name(), Err: err}
+	}
+	return func() error {
+		_ = unlockFile(f)
+		return f.Close()
+	}, nil
+}
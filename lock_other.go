@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd || openbsd || netbsd || dragonfly || illumos || windows)
+
+package efs
+
+import "os"
+
+// lockSupported is false: the lock file's presence is all IsLocked can see.
+const lockSupported = false
+
+func lockFile(f *os.File) error   { return nil }
+func unlockFile(f *os.File) error { return nil }
@@ -0,0 +1,43 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithLock(t *testing.T) {
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+
+	e, err := Extract(mem, ".", "locked", t.TempDir(), WithLock())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	if !IsLocked(e.Dir()) {
+		t.Error("expected extraction to be locked")
+	}
+	if err := e.Verify(); err != nil {
+		t.Errorf("lock file must not count as extra content: %v", err)
+	}
+	if err := e.Cleanup(); err != nil {
+		t.Fatalf("Cleanup error: %v", err)
+	}
+	if IsLocked(e.Dir()) {
+		t.Error("expected removed extraction to be unlocked")
+	}
+
+	plain := t.TempDir()
+	if IsLocked(plain) {
+		t.Error("expected directory without lock file to be unlocked")
+	}
+	if lockSupported {
+		// A stale lock file left by a crashed process holds no lock.
+		if err := os.WriteFile(filepath.Join(plain, LockFileName), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if IsLocked(plain) {
+			t.Error("expected stale lock file to be unlocked")
+		}
+	}
+}
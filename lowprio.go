@@ -0,0 +1,51 @@
+package efs
+
+import (
+	"runtime"
+	"time"
+)
+
+// Yield schedule of WithBackground: a short sleep after every yieldBytes
+// written or yieldFiles entries, whichever comes first.
+const (
+	yieldBytes = 1 << 20
+	yieldFiles = 32
+	yieldSleep = 2 * time.Millisecond
+)
+
+// WithBackground extracts with the lowest I/O priority the platform offers
+// (the idle class of ioprio_set on Linux, background mode on Windows) and
+// pauses briefly after every megabyte or 32 entries, so pre-warming an asset
+// cache does not degrade a latency-sensitive service sharing the disk. The
+// priority applies to the OS thread doing the extraction, which is locked
+// for the duration and restored afterwards. Elsewhere only the pauses apply.
+func WithBackground() Option {
+	return func(o *options) { o.lowPriority = true }
+}
+
+// lowerPriority switches the calling goroutine to a locked OS thread with
+// background I/O priority and returns a function that restores both.
+func lowerPriority() (restore func()) {
+	runtime.LockOSThread()
+	undo := setThreadBackground()
+	return func() {
+		undo()
+		runtime.UnlockOSThread()
+	}
+}
+
+// yielder spaces out the work of a WithBackground extraction.
+type yielder struct {
+	bytes int64
+	files int
+}
+
+// after records an applied entry of size bytes and sleeps when due.
+func (y *yielder) after(size int64) {
+	y.bytes += size
+	y.files++
+	if y.bytes >= yieldBytes || y.files >= yieldFiles {
+		time.Sleep(yieldSleep)
+		y.bytes, y.files = 0, 0
+	}
+}
@@ -0,0 +1,26 @@
+package efs
+
+import "syscall"
+
+// ioprio_set/ioprio_get arguments (linux/ioprio.h).
+const (
+	ioprioWhoProcess = 1       // IOPRIO_WHO_PROCESS; a thread ID selects that thread
+	ioprioIdle       = 3 << 13 // IOPRIO_CLASS_IDLE << IOPRIO_CLASS_SHIFT
+)
+
+// setThreadBackground puts the calling thread into the idle I/O class and
+// returns a function restoring its previous priority. Failures are ignored;
+// the priority is only a hint.
+func setThreadBackground() (undo func()) {
+	tid := uintptr(syscall.Gettid())
+	prev, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_GET, ioprioWhoProcess, tid, 0)
+	if errno != 0 {
+		return func() {}
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, tid, ioprioIdle); errno != 0 {
+		return func() {}
+	}
+	return func() {
+		syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, tid, prev)
+	}
+}
@@ -0,0 +1,29 @@
+package efs
+
+import (
+	"syscall"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithBackgroundIOPriority(t *testing.T) {
+	mem := fstest.MapFS{"assets/a.txt": {Data: []byte("A")}}
+
+	var during uintptr
+	onFile := WithOnFile(func(rel, path string) {
+		during, _, _ = syscall.Syscall(syscall.SYS_IOPRIO_GET, ioprioWhoProcess, uintptr(syscall.Gettid()), 0)
+	})
+	ex, err := Extract(mem, "assets", "bg", t.TempDir(), WithBackground(), onFile)
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	if during>>13 != ioprioIdle>>13 {
+		t.Errorf("expected the idle I/O class during extraction, got %#x", during)
+	}
+	after, _, _ := syscall.Syscall(syscall.SYS_IOPRIO_GET, ioprioWhoProcess, uintptr(syscall.Gettid()), 0)
+	if after>>13 == ioprioIdle>>13 {
+		t.Errorf("expected the I/O priority to be restored, got %#x", after)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !windows
+
+package efs
+
+// setThreadBackground does nothing; this platform has no per-thread I/O
+// priority that efs sets.
+func setThreadBackground() (undo func()) {
+	return func() {}
+}
@@ -0,0 +1,27 @@
+package efs
+
+import "syscall"
+
+var (
+	procGetCurrentThread  = syscall.NewLazyDLL("kernel32.dll").NewProc("GetCurrentThread")
+	procSetThreadPriority = syscall.NewLazyDLL("kernel32.dll").NewProc("SetThreadPriority")
+)
+
+// SetThreadPriority modes that lower I/O and memory priority.
+const (
+	threadModeBackgroundBegin = 0x00010000 // THREAD_MODE_BACKGROUND_BEGIN
+	threadModeBackgroundEnd   = 0x00020000 // THREAD_MODE_BACKGROUND_END
+)
+
+// setThreadBackground puts the calling thread into background processing
+// mode and returns a function ending it. Failures are ignored; the priority
+// is only a hint.
+func setThreadBackground() (undo func()) {
+	thread, _, _ := procGetCurrentThread.Call()
+	if ok, _, _ := procSetThreadPriority.Call(thread, threadModeBackgroundBegin); ok == 0 {
+		return func() {}
+	}
+	return func() {
+		procSetThreadPriority.Call(thread, threadModeBackgroundEnd)
+	}
+}
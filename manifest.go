@@ -0,0 +1,189 @@
+package efs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Manifest is an inventory of the regular files in an extracted tree.
+type Manifest struct {
+	ID    string          `json:"id,omitempty"` // Extraction ID, for manifests written by WithManifestFile
+	Files []ManifestEntry `json:"files"`        // Sorted by Path
+}
+
+// ManifestEntry describes one file of a Manifest.
+type ManifestEntry struct {
+	Path   string      `json:"path"` // Slash-separated, relative to the tree root
+	Size   int64       `json:"size"`
+	Mode   fs.FileMode `json:"mode"`
+	Digest string      `json:"digest"` // "<hash>:<hex>", "sha256:<hex>" by default (see WithHash)
+}
+
+// ChangeKind classifies a difference between a Manifest and a directory.
+type ChangeKind int
+
+const (
+	ChangeModified ChangeKind = iota // Content or size differs
+	ChangeMissing                    // File listed in the manifest no longer exists
+	ChangeExtra                      // File exists but is not listed in the manifest
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeModified:
+		return "modified"
+	case ChangeMissing:
+		return "missing"
+	case ChangeExtra:
+		return "extra"
+	}
+	return "unknown"
+}
+
+// Change is a single difference reported by Manifest.Check.
+type Change struct {
+	Path string // Slash-separated, relative to the tree root
+	Kind ChangeKind
+}
+
+// NewManifest hashes every regular file below dir and returns the resulting
+// inventory. Take it right after extraction to capture the expected state.
+// Files are hashed with SHA-256 unless WithHash selects another algorithm;
+// other options are ignored. Bookkeeping files written by efs, such as the
+// WithMetaFile marker and the WithManifestFile manifest, are left out.
+func NewManifest(dir string, opts ...Option) (*Manifest, error) {
+	h := newOptions(opts).digestHash()
+	m := &Manifest{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || reserved(filepath.ToSlash(rel)) {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		digest, err := fileDigest(h, path)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, ManifestEntry{
+			Path:   filepath.ToSlash(rel),
+			Size:   info.Size(),
+			Mode:   info.Mode(),
+			Digest: digest,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The walk is lexical per directory only; sort across the whole tree.
+	slices.SortFunc(m.Files, func(a, b ManifestEntry) int { return strings.Compare(a.Path, b.Path) })
+	return m, nil
+}
+
+// Check compares dir against the manifest and returns every modified, missing
+// and extra file, ordered by path. A missing dir reports all files missing.
+// Like NewManifest, it considers regular files only, so symlinks and other
+// special files are never reported as extra. Bookkeeping files written by
+// efs are not reported as extra either.
+func (m *Manifest) Check(dir string) ([]Change, error) {
+	var changes []Change
+	listed := make(map[string]bool, len(m.Files))
+	for _, e := range m.Files {
+		listed[e.Path] = true
+		path := filepath.Join(dir, filepath.FromSlash(e.Path))
+		info, err := os.Lstat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			changes = append(changes, Change{Path: e.Path, Kind: ChangeMissing})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() || info.Size() != e.Size {
+			changes = append(changes, Change{Path: e.Path, Kind: ChangeModified})
+			continue
+		}
+		h, err := hashFor(e.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Path, err)
+		}
+		digest, err := fileDigest(h, path)
+		if err != nil {
+			return nil, err
+		}
+		if digest != e.Digest {
+			changes = append(changes, Change{Path: e.Path, Kind: ChangeModified})
+		}
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil // Not recorded by NewManifest either
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel = filepath.ToSlash(rel); !listed[rel] && !reserved(rel) {
+			changes = append(changes, Change{Path: rel, Kind: ChangeExtra})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int { return strings.Compare(a.Path, b.Path) })
+	return changes, nil
+}
+
+// Guard re-verifies dir against m every interval until ctx is done, calling
+// onTamper whenever files are modified, deleted or added underneath the running
+// application. onTamper is only called again when the set of changes differs
+// from the previous report. Every check re-hashes the tree, so choose the
+// interval with the tree size in mind. Guard blocks; run it in its own
+// goroutine. It returns ctx.Err() once ctx is done, or the first error that
+// prevents a check. interval must be positive.
+func Guard(ctx context.Context, dir string, m *Manifest, interval time.Duration, onTamper func([]Change)) error {
+	if interval <= 0 {
+		return fmt.Errorf("guard: non-positive interval %v", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last []Change
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		changes, err := m.Check(dir)
+		if err != nil {
+			return err
+		}
+		if len(changes) > 0 && !slices.Equal(changes, last) {
+			onTamper(changes)
+		}
+		last = changes
+	}
+}
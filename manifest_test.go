@@ -0,0 +1,111 @@
+package efs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestManifestCheck(t *testing.T) {
+	mem := fstest.MapFS{
+		"a.txt":     {Data: []byte("A")},
+		"b.txt":     {Data: []byte("B")},
+		"sub/c.txt": {Data: []byte("C")},
+	}
+	dir, cleanup, err := ExtractToTemp(mem, ".", "manifest", t.TempDir())
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+
+	m, err := NewManifest(dir)
+	if err != nil {
+		t.Fatalf("NewManifest error: %v", err)
+	}
+	if len(m.Files) != 3 || m.Files[2].Path != "sub/c.txt" {
+		t.Fatalf("unexpected manifest: %+v", m.Files)
+	}
+	if changes, err := m.Check(dir); err != nil || len(changes) != 0 {
+		t.Fatalf("expected clean tree, got %v, %v", changes, err)
+	}
+
+	// Same size, different content must still be detected.
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("X"), 0o644)
+	os.Remove(filepath.Join(dir, "sub", "c.txt"))
+	os.WriteFile(filepath.Join(dir, "new.txt"), []byte("N"), 0o644)
+
+	changes, err := m.Check(dir)
+	if err != nil {
+		t.Fatalf("Check error: %v", err)
+	}
+	want := []Change{
+		{Path: "a.txt", Kind: ChangeModified},
+		{Path: "new.txt", Kind: ChangeExtra},
+		{Path: "sub/c.txt", Kind: ChangeMissing},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %v, got %v", want, changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d: expected %v, got %v", i, want[i], changes[i])
+		}
+	}
+}
+
+func TestGuardReportsTampering(t *testing.T) {
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+	dir, cleanup, err := ExtractToTemp(mem, ".", "guard", t.TempDir())
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+
+	m, err := NewManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	reported := make(chan []Change, 1)
+	go Guard(ctx, dir, m, 10*time.Millisecond, func(c []Change) {
+		reported <- c
+		cancel()
+	})
+
+	os.Remove(filepath.Join(dir, "a.txt"))
+	select {
+	case c := <-reported:
+		if len(c) != 1 || c[0].Kind != ChangeMissing {
+			t.Errorf("expected a.txt missing, got %v", c)
+		}
+	case <-ctx.Done():
+		t.Fatal("Guard did not report the deleted file")
+	}
+}
+
+func TestManifestIgnoresSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("A"), 0o644)
+	if err := os.Symlink("a.txt", filepath.Join(dir, "l")); err != nil {
+		t.Skipf("cannot create symlink: %v", err)
+	}
+
+	m, err := NewManifest(dir)
+	if err != nil {
+		t.Fatalf("NewManifest error: %v", err)
+	}
+	if changes, err := m.Check(dir); err != nil || len(changes) != 0 {
+		t.Errorf("expected intact tree, got %v, %v", changes, err)
+	}
+}
+
+func TestGuardRejectsInterval(t *testing.T) {
+	if err := Guard(context.Background(), t.TempDir(), &Manifest{}, 0, func([]Change) {}); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
@@ -0,0 +1,87 @@
+package efs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ManifestFileName is the name of the manifest written by WithManifestFile.
+const ManifestFileName = ".efs-manifest.json"
+
+// WithManifestFile writes a JSON Manifest of the extracted files (path,
+// size, mode and digest of each, plus the extraction ID) to ManifestFileName
+// in the extraction root once the extraction has succeeded, so operators can
+// audit what was unpacked and other tools can consume the inventory. Digests
+// use SHA-256 unless WithHash selects another algorithm. Only files that are
+// part of the extraction are listed, even when ExtractTo writes into a
+// directory with other content. Use Extraction.Manifest or ReadManifest to
+// get it back; Manifest.Check verifies a tree against it.
+func WithManifestFile() Option {
+	return func(o *options) { o.manifestFile = true }
+}
+
+// ReadManifest reads the manifest written by WithManifestFile from dir.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Manifest returns the manifest written by WithManifestFile, or nil without
+// that option or while a WithPriority extraction is still running.
+func (e *Extraction) Manifest() *Manifest {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.manifest
+}
+
+// writeManifest hashes the regular files of entries extracted into dir and
+// stores the manifest in dir. It returns nil without WithManifestFile.
+func (o *options) writeManifest(dir string, entries []planEntry) (*Manifest, error) {
+	if !o.manifestFile {
+		return nil, nil
+	}
+	h := o.digestHash()
+	m := &Manifest{ID: o.id}
+	for _, e := range entries {
+		if e.d.IsDir() {
+			continue
+		}
+		p := filepath.Join(dir, filepath.FromSlash(e.rel))
+		info, err := os.Lstat(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // Skipped or failed under WithContinueOnError
+			}
+			return nil, destErr(p, err)
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		digest, err := fileDigest(h, p)
+		if err != nil {
+			return nil, destErr(p, err)
+		}
+		m.Files = append(m.Files, ManifestEntry{Path: e.rel, Size: info.Size(), Mode: info.Mode(), Digest: digest})
+	}
+	slices.SortFunc(m.Files, func(a, b ManifestEntry) int { return strings.Compare(a.Path, b.Path) })
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	p := filepath.Join(dir, ManifestFileName)
+	if err := os.WriteFile(p, append(data, '\n'), o.filePerm()); err != nil {
+		return nil, destErr(p, err)
+	}
+	return m, destErr(p, o.applyOwner(p))
+}
@@ -0,0 +1,44 @@
+package efs
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestManifestFile(t *testing.T) {
+	mem := fstest.MapFS{
+		"b.txt":     {Data: []byte("BB")},
+		"a.txt":     {Data: []byte("A")},
+		"sub/c.txt": {Data: []byte("CCC")},
+	}
+
+	ex, err := Extract(mem, ".", "manifestfile", t.TempDir(), WithManifestFile(), WithExtractionID("job-1"))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	m := ex.Manifest()
+	if m == nil || m.ID != "job-1" || len(m.Files) != 3 {
+		t.Fatalf("unexpected manifest %+v", m)
+	}
+	if f := m.Files[2]; f.Path != "sub/c.txt" || f.Size != 3 || !f.Mode.IsRegular() || f.Digest == "" {
+		t.Errorf("unexpected entry %+v", f)
+	}
+
+	read, err := ReadManifest(ex.Dir())
+	if err != nil {
+		t.Fatalf("ReadManifest error: %v", err)
+	}
+	if len(read.Files) != 3 || read.Files[0] != m.Files[0] {
+		t.Errorf("expected %+v on disk, got %+v", m, read)
+	}
+
+	// The manifest describes the tree it lives in without listing itself.
+	if changes, err := read.Check(ex.Dir()); err != nil || len(changes) != 0 {
+		t.Errorf("expected clean tree, got %v, %v", changes, err)
+	}
+	if err := ex.Verify(); err != nil {
+		t.Errorf("expected Verify to ignore %s, got %v", ManifestFileName, err)
+	}
+}
@@ -0,0 +1,99 @@
+package efs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+)
+
+// MetaFileName is the name of the marker file written by WithMetaFile.
+const MetaFileName = ".efs-meta"
+
+// modulePath identifies this package in build info.
+const modulePath = "github.com/skabbio1976/eFS"
+
+// Meta describes an extraction directory. It is stored as JSON in
+// MetaFileName so that cleanup, repair and support tooling can reason about
+// directories found on disk long after the process that created them is gone.
+type Meta struct {
+	Roots   []string  `json:"roots"`        // Source roots, subject to WithRedaction
+	Prefix  string    `json:"prefix"`       // Temp directory prefix
+	PID     int       `json:"pid"`          // Process that created the directory
+	Created time.Time `json:"created"`      // Creation time
+	Version string    `json:"version"`      // efs module version, "(devel)" if unknown
+	ID      string    `json:"id,omitempty"` // Extraction ID (see WithExtractionID)
+}
+
+// WithMetaFile writes a MetaFileName marker into the root of every extraction
+// before any files are extracted, so even directories abandoned by a crash
+// mid-extraction can be attributed. The marker is ignored by Verify.
+func WithMetaFile() Option {
+	return func(o *options) { o.metaFile = true }
+}
+
+// ReadMeta reads the marker file written by WithMetaFile from dir.
+func ReadMeta(dir string) (*Meta, error) {
+	data, err := os.ReadFile(filepath.Join(dir, MetaFileName))
+	if err != nil {
+		return nil, err
+	}
+	var m Meta
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// writeMeta stores the marker for an extraction of sources into dir.
+func (o *options) writeMeta(dir, prefix string, sources []source) error {
+	if !o.metaFile {
+		return nil
+	}
+	m := Meta{
+		Prefix:  prefix,
+		PID:     os.Getpid(),
+		Created: time.Now().UTC(),
+		Version: moduleVersion(),
+		ID:      o.id,
+	}
+	for _, src := range sources {
+		m.Roots = append(m.Roots, o.display(src.root))
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, MetaFileName)
+	if err := os.WriteFile(path, append(data, '\n'), o.filePerm()); err != nil {
+		return err
+	}
+	return o.applyOwner(path)
+}
+
+// moduleVersion reports the version of this module linked into the binary.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}
+
+// reserved reports whether rel (relative to an extraction root) is a
+// bookkeeping file written by efs rather than extracted content.
+func reserved(rel string) bool {
+	return rel == MetaFileName || rel == LockFileName || rel == NoticesFileName || rel == ManifestFileName || rel == CompleteFileName
+}
@@ -0,0 +1,34 @@
+package efs
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestMetaFile(t *testing.T) {
+	mem := fstest.MapFS{"assets/a.txt": {Data: []byte("A")}}
+
+	ex, err := Extract(mem, "assets", "meta", t.TempDir(), WithMetaFile())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	m, err := ReadMeta(ex.Dir())
+	if err != nil {
+		t.Fatalf("ReadMeta error: %v", err)
+	}
+	if len(m.Roots) != 1 || m.Roots[0] != "assets" || m.Prefix != "meta" || m.PID != os.Getpid() {
+		t.Errorf("unexpected meta %+v", m)
+	}
+	if time.Since(m.Created) > time.Minute || m.Version == "" {
+		t.Errorf("unexpected created/version in %+v", m)
+	}
+
+	// The marker is bookkeeping, not extracted content.
+	if err := ex.Verify(); err != nil {
+		t.Errorf("expected Verify to ignore %s, got %v", MetaFileName, err)
+	}
+}
@@ -0,0 +1,109 @@
+package efs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidMigration is reported (wrapped in a *SourceError) when the
+// migration files given to ExtractMigrations are misnamed or out of order.
+var ErrInvalidMigration = errors.New("invalid migration")
+
+// Migration is one SQL migration extracted by ExtractMigrations.
+type Migration struct {
+	Version uint64 // Numeric prefix of the file name
+	Name    string // File name, e.g. "0002_add_index.sql" or "0002_add_index.up.sql"
+	Path    string // On-disk path of the migration
+	Down    string // On-disk path of the matching ".down.sql" file; "" if there is none
+}
+
+// ExtractMigrations extracts the SQL migrations in the directory root of
+// fsys into a new temporary directory, for migration runners that need files
+// on disk. Every ".sql" file directly in root must start with a numeric
+// version followed by "_" or "-" (e.g. "0001_init.sql"); files ending in
+// ".down.sql" are paired with the migration of the same version. Versions
+// must strictly increase in file name order, which rejects duplicates as well
+// as unpadded numbers such as "10_x.sql" sorting before "2_y.sql"; all of
+// this is checked before anything is written. The migrations are returned in
+// order. Other files are extracted but not listed.
+//
+// Example:
+//
+//	migrations, cleanup, err := efs.ExtractMigrations(assets, "migrations")
+//	if err != nil { return err }
+//	defer cleanup()
+//	for _, m := range migrations { apply(m.Version, m.Path) }
+func ExtractMigrations(fsys fs.FS, root string, opts ...Option) ([]Migration, func(), error) {
+	if root == "" {
+		root = "."
+	}
+	migrations, err := scanMigrations(fsys, root)
+	if err != nil {
+		return nil, nil, err
+	}
+	e, err := Extract(fsys, root, "migrations", "", opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, m := range migrations {
+		migrations[i].Path = e.Path(m.Path)
+		if m.Down != "" {
+			migrations[i].Down = e.Path(m.Down)
+		}
+	}
+	return migrations, func() { _ = e.Cleanup() }, nil
+}
+
+// scanMigrations lists and validates the migrations in root. Path and Down
+// hold the file names until the files are extracted.
+func scanMigrations(fsys fs.FS, root string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return nil, sourceErr(root, err)
+	}
+	invalid := func(name, format string, args ...any) error {
+		return &SourceError{Path: path.Join(root, name), Err: fmt.Errorf("%w %s: "+format, append([]any{ErrInvalidMigration, name}, args...)...)}
+	}
+
+	var migrations []Migration
+	var downs []Migration
+	for _, d := range entries { // ReadDir sorts by file name
+		name := d.Name()
+		if d.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+		digits := len(name) - len(strings.TrimLeft(name, "0123456789"))
+		if digits == 0 || digits == len(name) || (name[digits] != '_' && name[digits] != '-') {
+			return nil, invalid(name, "name must start with a version number followed by _ or -")
+		}
+		version, err := strconv.ParseUint(name[:digits], 10, 64)
+		if err != nil {
+			return nil, invalid(name, "%v", err)
+		}
+		m := Migration{Version: version, Name: name, Path: name}
+		list := &migrations
+		if strings.HasSuffix(name, ".down.sql") {
+			list = &downs
+		}
+		if n := len(*list); n > 0 && (*list)[n-1].Version >= version {
+			return nil, invalid(name, "version %d does not follow %d of %s", version, (*list)[n-1].Version, (*list)[n-1].Name)
+		}
+		*list = append(*list, m)
+	}
+
+	next := 0
+	for _, down := range downs {
+		for next < len(migrations) && migrations[next].Version < down.Version {
+			next++
+		}
+		if next == len(migrations) || migrations[next].Version != down.Version {
+			return nil, invalid(down.Name, "no migration with version %d", down.Version)
+		}
+		migrations[next].Down = down.Name
+	}
+	return migrations, nil
+}
@@ -0,0 +1,60 @@
+package efs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExtractMigrations(t *testing.T) {
+	SetDefaultBaseDir(t.TempDir())
+	defer SetDefaultBaseDir("")
+	mem := fstest.MapFS{
+		"db/0001_init.up.sql":   {Data: []byte("CREATE TABLE t (id int);")},
+		"db/0001_init.down.sql": {Data: []byte("DROP TABLE t;")},
+		"db/0002_index.sql":     {Data: []byte("CREATE INDEX i ON t (id);")},
+		"db/0010_seed.sql":      {Data: []byte("INSERT INTO t VALUES (1);")},
+		"db/README.md":          {Data: []byte("notes")},
+		"db/fixtures/extra.sql": {Data: []byte("ignored")},
+	}
+	migrations, cleanup, err := ExtractMigrations(mem, "db")
+	if err != nil {
+		t.Fatalf("ExtractMigrations error: %v", err)
+	}
+	defer cleanup()
+
+	if len(migrations) != 3 {
+		t.Fatalf("expected 3 migrations, got %+v", migrations)
+	}
+	for i, want := range []uint64{1, 2, 10} {
+		if migrations[i].Version != want {
+			t.Errorf("migration %d: expected version %d, got %d", i, want, migrations[i].Version)
+		}
+	}
+	if data, err := os.ReadFile(migrations[0].Down); err != nil || string(data) != "DROP TABLE t;" {
+		t.Errorf("expected down migration for version 1, got %q, %v", data, err)
+	}
+	if filepath.Base(migrations[2].Path) != "0010_seed.sql" || migrations[2].Down != "" {
+		t.Errorf("unexpected last migration %+v", migrations[2])
+	}
+}
+
+func TestExtractMigrationsInvalid(t *testing.T) {
+	SetDefaultBaseDir(t.TempDir())
+	defer SetDefaultBaseDir("")
+	for name, mem := range map[string]fstest.MapFS{
+		"unpadded":  {"db/10_b.sql": {}, "db/2_a.sql": {}},
+		"duplicate": {"db/1_a.sql": {}, "db/1_b.sql": {}},
+		"unversion": {"db/init.sql": {}},
+		"orphan":    {"db/1_a.sql": {}, "db/2_b.down.sql": {}},
+	} {
+		if _, _, err := ExtractMigrations(mem, "db"); !errors.Is(err, ErrInvalidMigration) {
+			t.Errorf("%s: expected ErrInvalidMigration, got %v", name, err)
+		}
+	}
+	if entries, _ := os.ReadDir(DefaultBaseDir()); len(entries) != 0 {
+		t.Errorf("expected nothing extracted for invalid migrations, got %d entries", len(entries))
+	}
+}
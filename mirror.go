@@ -0,0 +1,81 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// WithMirror makes ExtractTo, Plan.Apply and SyncToDir delete whatever in
+// the destination is not part of the extraction once it has succeeded, so a
+// persistent asset directory never serves stale files after an upgrade.
+// Entries left out by WithInclude, WithExclude, WithFilter or WithRename
+// count as not part of it; efs's own marker files are kept. A directory that
+// does not belong is removed with everything below it. Deletions are listed
+// in SyncResult.Deleted. A failure while pruning leaves the remaining extra
+// entries in place. WithAtomic already replaces the destination as a whole
+// and needs no pruning.
+func WithMirror() Option {
+	return func(o *options) { o.mirror = true }
+}
+
+// extras returns the slash-separated paths below dst that are not among
+// entries, listing a directory that does not belong once, without its
+// contents.
+func (o *options) extras(dst string, entries []planEntry) ([]string, error) {
+	keep := make(map[string]bool)
+	for _, e := range entries {
+		keep[e.rel] = true
+		if shim := o.shimName(e.rel); shim != "" {
+			keep[shim] = true
+		}
+		for dir := path.Dir(e.rel); dir != "."; dir = path.Dir(dir) {
+			keep[dir] = true
+		}
+	}
+
+	var extra []string
+	err := filepath.WalkDir(dst, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == dst {
+			return fs.SkipAll // Nothing there yet
+		}
+		if err != nil || p == dst {
+			return err
+		}
+		rel, err := filepath.Rel(dst, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if keep[rel] || reserved(rel) {
+			return nil
+		}
+		extra = append(extra, rel)
+		if d.IsDir() {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	return extra, err
+}
+
+// prune removes everything below dst that is not among entries.
+func (o *options) prune(dst string, entries []planEntry) error {
+	extra, err := o.extras(dst, entries)
+	if err != nil {
+		return destErr(dst, err)
+	}
+	for i, rel := range extra {
+		p := filepath.Join(dst, filepath.FromSlash(rel))
+		if rmErr := o.traceOp("prune", p, func() error { return os.RemoveAll(p) }); rmErr != nil {
+			extra, err = extra[:i], destErr(p, rmErr)
+			break
+		}
+	}
+	if o.syncTo != nil {
+		o.syncTo.Deleted = append(o.syncTo.Deleted, extra...)
+	}
+	return err
+}
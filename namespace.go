@@ -0,0 +1,111 @@
+package efs
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// WithNamespace creates temporary entries in the subdirectory "efs-<app>" of
+// the base directory (inside the per-user directory with WithUserIsolation),
+// creating it with mode 0700 if needed. Grouping an application's
+// extractions under one parent allows bulk cleanup and simple age-based
+// garbage collection, and keeps libraries in the same process that happen to
+// use the same tempPrefix apart. app must satisfy the rules for tempPrefix
+// (see ErrInvalidPrefix). The namespace directory is left in place by
+// Cleanup. See Namespace for a scoped extractor.
+func WithNamespace(app string) Option {
+	return func(o *options) { o.namespace = app }
+}
+
+// Scope is an extractor whose temporary entries all live in one namespace
+// directory; see Namespace. It is safe for concurrent use.
+type Scope struct {
+	app  string
+	opts []Option
+}
+
+// Namespace returns a Scope for appName whose extractions are created with
+// WithNamespace(appName), so they all live under <base>/efs-<appName>/. opts
+// are applied to every extraction of the Scope, before the options of the
+// individual call. It fails with ErrInvalidPrefix if appName cannot be used
+// as a directory name.
+//
+// Example:
+//
+//	ns, err := efs.Namespace("myapp")
+//	if err != nil { return err }
+//	ex, err := ns.Extract(assets, "assets", "web")
+func Namespace(appName string, opts ...Option) (*Scope, error) {
+	if err := validatePrefix(appName); err != nil {
+		return nil, err
+	}
+	if appName == "" {
+		return nil, fmt.Errorf("%w: empty namespace", ErrInvalidPrefix)
+	}
+	opts = append(slices.Clip(opts), WithNamespace(appName))
+	return &Scope{app: appName, opts: opts}, nil
+}
+
+// options returns the Scope options followed by opts.
+func (s *Scope) options(opts []Option) []Option {
+	return append(slices.Clip(s.opts), opts...)
+}
+
+// Extract is Extract with the Scope's options and default base directory.
+func (s *Scope) Extract(fsys fs.FS, root, tempPrefix string, opts ...Option) (*Extraction, error) {
+	return Extract(fsys, root, tempPrefix, "", s.options(opts)...)
+}
+
+// ExtractToTemp is ExtractToTemp with the Scope's options and default base
+// directory.
+func (s *Scope) ExtractToTemp(fsys fs.FS, root, tempPrefix string, opts ...Option) (string, func(), error) {
+	return ExtractToTemp(fsys, root, tempPrefix, "", s.options(opts)...)
+}
+
+// ExtractFile is ExtractFile with the Scope's options and default base
+// directory.
+func (s *Scope) ExtractFile(fsys fs.FS, filePath, tempPrefix string, opts ...Option) (string, func(), error) {
+	return ExtractFile(fsys, filePath, tempPrefix, "", s.options(opts)...)
+}
+
+// Dir returns the namespace directory, creating it if needed. Extractions
+// that had to use a WithFallbackDirs directory live in the namespace
+// directory of that fallback instead.
+func (s *Scope) Dir() (string, error) {
+	o := newOptions(s.opts)
+	base := o.tempBase("", 0)
+	dir, err := o.isolate(base)
+	if err != nil {
+		return "", destErr(base, err)
+	}
+	return filepath.Abs(dir)
+}
+
+// RemoveAll removes the namespace directory with every extraction in it,
+// for bulk cleanup at shutdown or startup. Extractions still in use lose
+// their files; their Cleanup then has nothing left to do.
+func (s *Scope) RemoveAll() error {
+	dir, err := s.Dir()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// namespaceDir returns the WithNamespace directory in dir, creating it.
+func (o *options) namespaceDir(dir string) (string, error) {
+	if o.namespace == "" {
+		return dir, nil
+	}
+	if err := validatePrefix(o.namespace); err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "efs-"+o.namespace)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
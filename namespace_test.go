@@ -0,0 +1,52 @@
+package efs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNamespace(t *testing.T) {
+	base := t.TempDir()
+	mem := fstest.MapFS{"assets/a.txt": {Data: []byte("A")}}
+
+	ns, err := Namespace("myapp", WithTempDir(base))
+	if err != nil {
+		t.Fatalf("Namespace error: %v", err)
+	}
+	dir, err := ns.Dir()
+	if err != nil || dir != filepath.Join(base, "efs-myapp") {
+		t.Fatalf("expected %s, got %q (%v)", filepath.Join(base, "efs-myapp"), dir, err)
+	}
+
+	ex, err := ns.Extract(mem, "assets", "web")
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	file, cleanup, err := ns.ExtractFile(mem, "assets/a.txt", "cfg")
+	if err != nil {
+		t.Fatalf("ExtractFile error: %v", err)
+	}
+	defer cleanup()
+	for _, p := range []string{ex.Dir(), file} {
+		if filepath.Dir(p) != dir {
+			t.Errorf("expected %s inside %s", p, dir)
+		}
+	}
+
+	if err := ns.RemoveAll(); err != nil {
+		t.Fatalf("RemoveAll error: %v", err)
+	}
+	if _, err := os.Stat(ex.Dir()); !os.IsNotExist(err) {
+		t.Errorf("expected RemoveAll to remove %s, got %v", ex.Dir(), err)
+	}
+	if err := ex.Cleanup(); err != nil {
+		t.Errorf("Cleanup after RemoveAll: %v", err)
+	}
+
+	if _, err := Namespace("a/b"); !errors.Is(err, ErrInvalidPrefix) {
+		t.Errorf("expected ErrInvalidPrefix, got %v", err)
+	}
+}
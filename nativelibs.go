@@ -0,0 +1,111 @@
+package efs
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// NativeLibs is a set of shared libraries extracted by SetupNativeLibs.
+type NativeLibs struct {
+	Dir   string   // Extraction root
+	Var   string   // Variable the dynamic loader searches: LD_LIBRARY_PATH, DYLD_LIBRARY_PATH or PATH
+	Paths []string // Directories holding libraries, in search order
+
+	ex *Extraction
+}
+
+// SetupNativeLibs extracts the shared libraries (and anything else) below
+// root in fsys into a new temporary directory and works out how child
+// processes find them: every directory holding a .so, .dylib or .dll file is
+// prepended to the platform's loader search variable, LD_LIBRARY_PATH on
+// Linux and the BSDs, DYLD_LIBRARY_PATH on macOS and PATH on Windows. This
+// lets cgo-dependent helper programs run from libraries embedded in the
+// binary. The current process is not affected; pass the result of Environ
+// to exec.Cmd.Env or use ApplyTo. If root is empty, "." is used.
+//
+// Example:
+//
+//	libs, err := efs.SetupNativeLibs(assets, "lib")
+//	if err != nil { return err }
+//	defer libs.Cleanup()
+//	cmd := exec.Command(helperPath)
+//	libs.ApplyTo(cmd)
+func SetupNativeLibs(fsys fs.FS, root string, opts ...Option) (*NativeLibs, error) {
+	ex, err := Extract(fsys, root, "native", "", opts...)
+	if err != nil {
+		return nil, err
+	}
+	n := &NativeLibs{Dir: ex.Dir(), Var: loaderPathVar(), ex: ex}
+	for _, e := range ex.entries {
+		if e.d.IsDir() || !isSharedLibrary(path.Base(e.rel)) {
+			continue
+		}
+		if dir := ex.Path(path.Dir(e.rel)); !slices.Contains(n.Paths, dir) {
+			n.Paths = append(n.Paths, dir)
+		}
+	}
+	return n, nil
+}
+
+// loaderPathVar returns the environment variable the dynamic loader of the
+// current platform searches for shared libraries.
+func loaderPathVar() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "PATH"
+	case "darwin", "ios":
+		return "DYLD_LIBRARY_PATH"
+	}
+	return "LD_LIBRARY_PATH"
+}
+
+// isSharedLibrary reports whether the file name looks like a shared library,
+// including versioned names such as libfoo.so.1.2.
+func isSharedLibrary(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasSuffix(name, ".so") || strings.Contains(name, ".so.") ||
+		strings.HasSuffix(name, ".dylib") || strings.HasSuffix(name, ".dll")
+}
+
+// Environ returns a copy of env (in os.Environ form) with the library
+// directories prepended to Var, keeping any previous value after them. On
+// Windows the variable name is matched case-insensitively, as Windows does.
+func (n *NativeLibs) Environ(env []string) []string {
+	out := slices.Clone(env)
+	if len(n.Paths) == 0 {
+		return out
+	}
+	value := strings.Join(n.Paths, string(os.PathListSeparator))
+	for i, kv := range out {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || !(k == n.Var || runtime.GOOS == "windows" && strings.EqualFold(k, n.Var)) {
+			continue
+		}
+		if v != "" {
+			value += string(os.PathListSeparator) + v
+		}
+		out[i] = k + "=" + value
+		return out
+	}
+	return append(out, n.Var+"="+value)
+}
+
+// ApplyTo sets cmd.Env so the command finds the libraries, starting from
+// cmd.Env or, if that is nil, the environment of the current process.
+func (n *NativeLibs) ApplyTo(cmd *exec.Cmd) {
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = n.Environ(env)
+}
+
+// Cleanup removes the extracted libraries. It is idempotent.
+func (n *NativeLibs) Cleanup() error {
+	return n.ex.Cleanup()
+}
@@ -0,0 +1,56 @@
+package efs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSetupNativeLibs(t *testing.T) {
+	SetDefaultBaseDir(t.TempDir())
+	defer SetDefaultBaseDir("")
+	mem := fstest.MapFS{
+		"lib/libfoo.so.1":    {Data: []byte("ELF")},
+		"lib/sub/bar.dylib":  {Data: []byte("MachO")},
+		"lib/sub/baz.dll":    {Data: []byte("PE")},
+		"lib/share/data.txt": {Data: []byte("not a library")},
+	}
+	libs, err := SetupNativeLibs(mem, "lib")
+	if err != nil {
+		t.Fatalf("SetupNativeLibs error: %v", err)
+	}
+	defer libs.Cleanup()
+
+	want := []string{libs.Dir, filepath.Join(libs.Dir, "sub")}
+	if !slices.Equal(libs.Paths, want) {
+		t.Fatalf("expected library dirs %v, got %v", want, libs.Paths)
+	}
+
+	sep := string(os.PathListSeparator)
+	env := libs.Environ([]string{"HOME=/home/u", libs.Var + "=/opt/lib"})
+	if got := env[1]; got != libs.Var+"="+strings.Join(want, sep)+sep+"/opt/lib" {
+		t.Errorf("expected library dirs prepended, got %q", got)
+	}
+	env = libs.Environ([]string{"HOME=/home/u"})
+	if got := env[len(env)-1]; got != libs.Var+"="+strings.Join(want, sep) {
+		t.Errorf("expected variable to be added, got %q", got)
+	}
+
+	cmd := exec.Command("true")
+	cmd.Env = []string{"A=1"}
+	libs.ApplyTo(cmd)
+	if len(cmd.Env) != 2 || cmd.Env[0] != "A=1" {
+		t.Errorf("expected ApplyTo to extend cmd.Env, got %v", cmd.Env)
+	}
+
+	if err := libs.Cleanup(); err != nil {
+		t.Fatalf("Cleanup error: %v", err)
+	}
+	if _, err := os.Stat(libs.Dir); !os.IsNotExist(err) {
+		t.Errorf("expected libraries removed, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package efs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ErrNoExecMount is reported (wrapped in a *DestError) when files marked with
+// WithExecutables or WithExecAll would be extracted onto a file system
+// mounted noexec, where running them would later fail with a confusing EPERM
+// or EACCES.
+var ErrNoExecMount = errors.New("file system is mounted noexec")
+
+// WithExecRelocate moves an extraction containing executables out of a
+// noexec base directory (a hardened /tmp, or /dev/shm with WithPreferTmpfs)
+// into an "efs" directory below the user cache directory, if that is on an
+// exec-capable file system, instead of failing with ErrNoExecMount. An
+// explicit tempDir argument or WithTempDir is relocated as well. It has no
+// effect on ExtractTo and Plan.Apply, whose destination is fixed.
+func WithExecRelocate() Option {
+	return func(o *options) { o.execRelocate = true }
+}
+
+// hasExecutables reports whether any file of entries is a program.
+func (o *options) hasExecutables(entries []planEntry) bool {
+	if len(o.executables) == 0 && len(o.execAll) == 0 {
+		return false
+	}
+	return slices.ContainsFunc(entries, func(e planEntry) bool {
+		return !e.d.IsDir() && o.wantsExecBit(e.rel)
+	})
+}
+
+// execBase returns baseDir, or with WithExecRelocate an exec-capable
+// replacement, when programs are to be extracted below it. It fails with
+// ErrNoExecMount if baseDir is on a noexec mount and cannot be replaced.
+func (o *options) execBase(baseDir string, programs bool) (string, error) {
+	if !programs || !noexecMount(existingDir(baseDir)) {
+		return baseDir, nil
+	}
+	if o.execRelocate {
+		if cache, err := os.UserCacheDir(); err == nil && !noexecMount(existingDir(cache)) {
+			dir := filepath.Join(cache, "efs")
+			if err := os.MkdirAll(dir, 0o700); err == nil {
+				return dir, nil
+			}
+		}
+	}
+	return "", &DestError{Path: baseDir, Err: ErrNoExecMount}
+}
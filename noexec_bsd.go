@@ -0,0 +1,14 @@
+//go:build darwin || freebsd
+
+package efs
+
+import "syscall"
+
+// mntNoexec is MNT_NOEXEC in the f_flags reported by statfs.
+const mntNoexec = 0x4
+
+// noexecMount reports whether dir is on a file system mounted noexec.
+func noexecMount(dir string) bool {
+	var st syscall.Statfs_t
+	return syscall.Statfs(dir, &st) == nil && uint64(st.Flags)&mntNoexec != 0
+}
@@ -0,0 +1,12 @@
+package efs
+
+import "syscall"
+
+// stNoexec is ST_NOEXEC in the f_flags reported by statfs.
+const stNoexec = 0x8
+
+// noexecMount reports whether dir is on a file system mounted noexec.
+func noexecMount(dir string) bool {
+	var st syscall.Statfs_t
+	return syscall.Statfs(dir, &st) == nil && st.Flags&stNoexec != 0
+}
@@ -0,0 +1,57 @@
+package efs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNoExecMount(t *testing.T) {
+	dir := t.TempDir()
+	if err := syscall.Mount("tmpfs", dir, "tmpfs", syscall.MS_NOEXEC, "size=1m"); err != nil {
+		t.Skipf("cannot mount tmpfs: %v", err)
+	}
+	defer syscall.Unmount(dir, 0)
+
+	mem := fstest.MapFS{
+		"root/bin/tool":  {Data: []byte("#!/bin/sh\n")},
+		"root/readme.md": {Data: []byte("R")},
+	}
+
+	// Plain assets do not care about noexec.
+	ex, err := Extract(mem, "root", "plain", dir)
+	if err != nil {
+		t.Fatalf("Extract without executables error: %v", err)
+	}
+	ex.Cleanup()
+
+	_, err = Extract(mem, "root", "exec", dir, WithExecutables("bin/*"))
+	var de *DestError
+	if !errors.Is(err, ErrNoExecMount) || !errors.As(err, &de) || de.Path != dir {
+		t.Fatalf("expected ErrNoExecMount for %s, got %v", dir, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected nothing written to the noexec mount, got %d entries", len(entries))
+	}
+	if _, _, err := ExtractFile(mem, "root/bin/tool", "exec", dir, WithExecutables("root/bin/*")); !errors.Is(err, ErrNoExecMount) {
+		t.Errorf("expected ErrNoExecMount from ExtractFile, got %v", err)
+	}
+	if err := ExtractTo(mem, "root", filepath.Join(dir, "app"), WithExecutables("bin/*")); !errors.Is(err, ErrNoExecMount) {
+		t.Errorf("expected ErrNoExecMount from ExtractTo, got %v", err)
+	}
+
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	ex, err = Extract(mem, "root", "exec", dir, WithExecutables("bin/*"), WithExecRelocate())
+	if err != nil {
+		t.Fatalf("Extract with relocation error: %v", err)
+	}
+	defer ex.Cleanup()
+	if !strings.HasPrefix(ex.Dir(), filepath.Join(cache, "efs")+string(filepath.Separator)) {
+		t.Errorf("expected relocation below %s, got %s", cache, ex.Dir())
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd
+
+package efs
+
+// noexecMount reports false: mount flags are not available on this platform.
+func noexecMount(dir string) bool {
+	return false
+}
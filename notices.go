@@ -0,0 +1,80 @@
+package efs
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// NoticesFileName is the name of the combined notices file written by
+// WithNotices.
+const NoticesFileName = "THIRD_PARTY_NOTICES"
+
+// noticeNames are the base-name prefixes, in upper case, of files collected
+// by WithNotices.
+var noticeNames = []string{"LICENSE", "LICENCE", "NOTICE", "COPYING"}
+
+// WithNotices collects the license and notice files found in the source
+// (LICENSE, LICENCE, NOTICE and COPYING in any case, alone or followed by a
+// suffix such as LICENSE.txt or LICENSE-MIT) into a single NoticesFileName
+// file in the extraction root, as compliance reviews ask for when assets
+// from other projects are bundled. Each file appears under a header naming
+// its destination path, in extraction order. The collected files are
+// extracted as usual as well. No file is written if none are found or if the
+// source provides its own NoticesFileName at the root. The combined file is
+// ignored by Verify.
+func WithNotices() Option {
+	return func(o *options) { o.notices = true }
+}
+
+// isNotice reports whether the base name of rel marks a license or notice file.
+func isNotice(rel string) bool {
+	name := strings.ToUpper(path.Base(rel))
+	for _, prefix := range noticeNames {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && (rest == "" || strings.ContainsRune(".-_", rune(rest[0]))) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeNotices writes the combined notices of entries into dir if
+// WithNotices was given.
+func (o *options) writeNotices(fsys fs.FS, entries []planEntry, dir string) error {
+	if !o.notices {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, e := range entries {
+		if e.rel == NoticesFileName {
+			return nil // The source ships its own
+		}
+		if !e.d.Type().IsRegular() || !isNotice(e.rel) {
+			continue
+		}
+		data, err := o.readSource(fsys, e.src)
+		if err != nil {
+			return o.redactErr(sourceErr(e.src, err), e.src)
+		}
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		rule := strings.Repeat("=", 80) + "\n"
+		buf.WriteString(rule + e.rel + "\n" + rule + "\n")
+		buf.Write(data)
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			buf.WriteString("\n")
+		}
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	p := filepath.Join(dir, NoticesFileName)
+	if err := os.WriteFile(p, buf.Bytes(), o.filePerm()); err != nil {
+		return destErr(p, err)
+	}
+	return destErr(p, o.applyOwner(p))
+}
@@ -0,0 +1,63 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithNotices(t *testing.T) {
+	mem := fstest.MapFS{
+		"assets/index.html":                {Data: []byte("<html>")},
+		"assets/vendor/jquery/LICENSE.txt": {Data: []byte("MIT License")},
+		"assets/vendor/icons/NOTICE":       {Data: []byte("Icons by someone\n")},
+		"assets/vendor/font/license-ofl":   {Data: []byte("SIL OFL")},
+		"assets/vendor/font/licenses.go":   {Data: []byte("package x")},
+	}
+
+	ex, err := Extract(mem, "assets", "notices", t.TempDir(), WithNotices())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	data, err := os.ReadFile(ex.Path(NoticesFileName))
+	if err != nil {
+		t.Fatalf("read notices: %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"vendor/jquery/LICENSE.txt\n", "MIT License\n",
+		"vendor/icons/NOTICE\n", "Icons by someone\n",
+		"vendor/font/license-ofl\n", "SIL OFL\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("notices missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "index.html") || strings.Contains(got, "licenses.go") {
+		t.Errorf("notices include a non-notice file:\n%s", got)
+	}
+	if _, err := os.Stat(ex.Path("vendor/icons/NOTICE")); err != nil {
+		t.Errorf("notice file not extracted itself: %v", err)
+	}
+	if err := ex.Verify(); err != nil {
+		t.Errorf("expected Verify to ignore %s, got %v", NoticesFileName, err)
+	}
+}
+
+func TestWithNoticesNone(t *testing.T) {
+	mem := fstest.MapFS{"assets/a.txt": {Data: []byte("A")}}
+
+	ex, err := Extract(mem, "assets", "notices", t.TempDir(), WithNotices())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	if _, err := os.Stat(filepath.Join(ex.Dir(), NoticesFileName)); !os.IsNotExist(err) {
+		t.Errorf("expected no %s without notice files, got %v", NoticesFileName, err)
+	}
+}
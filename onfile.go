@@ -0,0 +1,15 @@
+package efs
+
+// WithOnFile calls fn for every file as soon as it has been completely
+// written, with its slash-separated path relative to the extraction root and
+// its absolute path on disk. A consumer can start processing early files
+// (e.g. parsing templates) while the rest of the tree is still being written.
+// Calls are never concurrent and happen in extraction order (see
+// WithOrdered). Under WithConcurrency they come from the worker goroutines
+// in completion order, or with WithOrdered in plan order, a file waiting for
+// the earlier ones. fn should hand longer work off instead of blocking.
+// Skipped files and directories are not reported. It applies to directory
+// extractions such as ExtractToTemp and ExtractTo.
+func WithOnFile(fn func(rel, path string)) Option {
+	return func(o *options) { o.onFile = fn }
+}
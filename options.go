@@ -0,0 +1,157 @@
+package efs
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"runtime"
+	"time"
+)
+
+// Option configures optional extraction behavior. Options are passed as trailing
+// arguments to ExtractToTemp and ExtractFile, or to NewExtractor; calls without
+// options keep the default behavior.
+type Option func(*options)
+
+// options holds the resolved configuration for a single extraction.
+type options struct {
+	tempDir   string
+	prefix    string
+	hasPrefix bool
+	ctx       context.Context
+
+	chown    bool
+	uid, gid int
+	xattrs   bool
+
+	selinuxLabel string
+
+	fileMode    fs.FileMode
+	dirMode     fs.FileMode
+	exactPerms  bool
+	strictPerms bool
+
+	redact func(name string) string
+	hash   Hash
+	audit  *auditLog
+
+	metaFile     bool
+	manifestFile bool
+	notices      bool
+	conflict     ConflictPolicy
+	atomic       bool
+	ordered      bool
+	priority     []string
+
+	skipEmptyDirs bool
+	include       []string
+	exclude       []string
+	filter        func(path string, d fs.DirEntry) bool
+	rename        func(srcPath string) (dstRel string, skip bool)
+
+	winHidden, winSystem bool
+	winPatterns          []string
+
+	clearQuarantine bool
+	preferTmpfs     bool
+	runtimeDir      bool
+	stageNear       string
+	spaceCheck      bool
+
+	fallbackDirs  []string
+	userIsolation bool
+	namespace     string
+
+	noAtime, sequentialRead bool
+	strictSource            bool
+
+	symlinks           bool
+	symlinkFallback    SymlinkFallback
+	unconfinedSymlinks bool
+	reparse            ReparsePolicy
+
+	lock      bool
+	immutable bool
+
+	privateACL bool
+
+	executables  []string
+	execAll      []string
+	cmdShims     bool
+	execRelocate bool
+
+	softBytes, softFiles int64
+	softWarn             func(LimitWarning)
+
+	lowPriority bool
+	concurrency int
+
+	retries int
+	backoff time.Duration
+
+	id       string
+	idInName bool
+
+	contentName bool
+	planned     []planEntry // Walked before extract, as by ReuseOrExtract
+
+	errReporter     func(err error, ec ErrorContext)
+	continueOnError bool
+
+	chdir bool
+
+	onFile    func(rel, path string)
+	progress  func(Progress)
+	reportTo  *Report
+	syncTo    *SyncResult
+	mirror    bool
+	cacheDir  string
+	onCleanup func(CleanupStats)
+	nameGen   func(prefix string) string
+
+	decompressors map[string]func(io.Reader) (io.ReadCloser, error)
+}
+
+// newOptions applies opts in order; later options override earlier ones.
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// WithOwner makes every extracted file and directory (including the temp root
+// itself) owned by uid and gid. This lets a bootstrapper running as root hand
+// assets directly to a service user without a separate chown -R pass.
+// Changing ownership usually requires elevated privileges; failures abort the
+// extraction. WithOwner is a no-op on Windows.
+func WithOwner(uid, gid int) Option {
+	return func(o *options) {
+		o.chown = true
+		o.uid, o.gid = uid, gid
+	}
+}
+
+// applyOwner changes the owner of path if WithOwner was given.
+func (o *options) applyOwner(path string) error {
+	if !o.chown || runtime.GOOS == "windows" {
+		return nil
+	}
+	return os.Lchown(path, o.uid, o.gid)
+}
+
+// finish applies the per-entry metadata options to dst after it has been
+// written from src in fsys.
+func (o *options) finish(fsys fs.FS, src, dst string) error {
+	if err := o.applyXattrs(fsys, src, dst); err != nil {
+		return err
+	}
+	if err := o.applySELinuxLabel(dst); err != nil {
+		return err
+	}
+	return o.applyOwner(dst)
+}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithOwner(t *testing.T) {
+	mem := fstest.MapFS{"sub/a.txt": {Data: []byte("A")}}
+
+	// Chowning to ourselves is always permitted, which keeps the test unprivileged.
+	dir, cleanup, err := ExtractToTemp(mem, ".", "owner", "", WithOwner(os.Getuid(), os.Getgid()))
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+
+	info, err := os.Stat(filepath.Join(dir, "sub", "a.txt"))
+	if err != nil {
+		t.Fatalf("expected sub/a.txt: %v", err)
+	}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok && int(st.Uid) != os.Getuid() {
+		t.Errorf("expected uid %d, got %d", os.Getuid(), st.Uid)
+	}
+}
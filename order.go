@@ -0,0 +1,25 @@
+package efs
+
+import (
+	"slices"
+	"strings"
+)
+
+// WithOrdered guarantees that entries are extracted in lexical order of their
+// slash-separated destination paths, the same order Manifest and Change lists
+// use. Per-file callbacks and audit log records follow this order, also when
+// several roots are combined, the source lists directories unsorted or
+// WithConcurrency writes files in parallel, so logs and manifests are
+// reproducible across runs and platforms. Parent directories always sort
+// before their contents.
+//
+// Without WithOrdered, entries are extracted in fs.WalkDir order, which is
+// lexical per directory but not across the whole tree.
+func WithOrdered() Option {
+	return func(o *options) { o.ordered = true }
+}
+
+// sortEntries orders entries lexically by destination path.
+func sortEntries(entries []planEntry) {
+	slices.SortStableFunc(entries, func(a, b planEntry) int { return strings.Compare(a.rel, b.rel) })
+}
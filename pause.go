@@ -0,0 +1,38 @@
+package efs
+
+import "context"
+
+// Pause suspends the background part of a WithPriority extraction after the
+// entry currently being written, so an application can give disk bandwidth
+// back to a foreground task. Resume continues where it stopped. While paused,
+// Wait and WaitFor for entries not yet written block; Cleanup still stops
+// the extraction. Pause has no effect once the extraction is complete or
+// without WithPriority, whose Extract returns only when everything is on
+// disk.
+func (e *Extraction) Pause() {
+	if bg := e.bg; bg != nil {
+		bg.mu.Lock()
+		bg.paused = !bg.done
+		bg.mu.Unlock()
+	}
+}
+
+// Resume continues a background extraction suspended by Pause.
+func (e *Extraction) Resume() {
+	if bg := e.bg; bg != nil {
+		bg.mu.Lock()
+		bg.paused = false
+		bg.mu.Unlock()
+		bg.changed.Broadcast()
+	}
+}
+
+// hold blocks while the background extraction is paused and ctx is live.
+func (bg *background) hold(ctx context.Context) error {
+	bg.mu.Lock()
+	defer bg.mu.Unlock()
+	for bg.paused && ctx.Err() == nil {
+		bg.changed.Wait()
+	}
+	return ctx.Err()
+}
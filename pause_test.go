@@ -0,0 +1,85 @@
+package efs
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// gatedFS blocks opening the file gate until release is closed, after
+// signaling entered.
+type gatedFS struct {
+	base    fstest.MapFS
+	gate    string
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (g gatedFS) Open(name string) (fs.File, error) {
+	if name == g.gate {
+		close(g.entered)
+		<-g.release
+	}
+	return g.base.Open(name)
+}
+
+func TestPauseResume(t *testing.T) {
+	g := gatedFS{
+		base: fstest.MapFS{
+			"root/ui/launcher.html": {Data: []byte("L")},
+			"root/data/a.bin":       {Data: []byte("A")},
+			"root/data/b.bin":       {Data: []byte("B")},
+		},
+		gate:    "root/data/a.bin",
+		entered: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	ex, err := Extract(g, "root", "pause", t.TempDir(), WithOrdered(), WithPriority("ui/**"))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	<-g.entered
+	ex.Pause()
+	close(g.release)
+	if err := ex.WaitFor("data/a.bin"); err != nil {
+		t.Fatalf("WaitFor error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, err := os.Stat(ex.Path("data/b.bin")); !os.IsNotExist(err) {
+		t.Fatalf("expected data/b.bin to wait while paused, got %v", err)
+	}
+
+	ex.Resume()
+	if err := ex.Wait(); err != nil {
+		t.Fatalf("Wait error: %v", err)
+	}
+	if _, err := os.Stat(ex.Path("data/b.bin")); err != nil {
+		t.Errorf("expected data/b.bin after Resume, got %v", err)
+	}
+}
+
+func TestPauseCleanup(t *testing.T) {
+	mem := fstest.MapFS{
+		"root/first.txt": {Data: []byte("F")},
+		"root/rest.txt":  {Data: []byte("R")},
+	}
+	ex, err := Extract(mem, "root", "pause", t.TempDir(), WithPriority("first.txt"))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	ex.Pause()
+	done := make(chan error)
+	go func() { done <- ex.Cleanup() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Cleanup error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Cleanup blocked on a paused extraction")
+	}
+}
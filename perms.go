@@ -0,0 +1,156 @@
+package efs
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Default permissions for extracted entries. They are passed to the create
+// calls and therefore filtered through the process umask unless WithExactPerms
+// is used.
+const (
+	defaultFileMode fs.FileMode = 0o644
+	defaultDirMode  fs.FileMode = 0o755
+)
+
+// WithFileMode sets the permission bits for extracted files (default 0o644).
+// Only the permission bits of mode are used.
+func WithFileMode(mode fs.FileMode) Option {
+	return func(o *options) { o.fileMode = mode.Perm() }
+}
+
+// WithDirMode sets the permission bits for extracted directories below the
+// temp root (default 0o755). The temp root itself keeps the 0o700 mode chosen
+// by os.MkdirTemp. Only the permission bits of mode are used.
+func WithDirMode(mode fs.FileMode) Option {
+	return func(o *options) { o.dirMode = mode.Perm() }
+}
+
+// WithExactPerms applies the requested file and directory modes literally with
+// an explicit Chmod after creation, instead of letting the process umask
+// filter them. Without it, 0o644 under a umask of 077 results in 0o600; with
+// it, the file ends up 0o644 regardless of umask.
+func WithExactPerms() Option {
+	return func(o *options) { o.exactPerms = true }
+}
+
+func (o *options) filePerm() fs.FileMode {
+	if o.fileMode != 0 {
+		return o.fileMode
+	}
+	return defaultFileMode
+}
+
+func (o *options) dirPerm() fs.FileMode {
+	if o.dirMode != 0 {
+		return o.dirMode
+	}
+	return defaultDirMode
+}
+
+// applyPerm enforces perm on path when exact permissions were requested.
+func (o *options) applyPerm(path string, perm fs.FileMode) error {
+	if !o.exactPerms {
+		return nil
+	}
+	return o.traceOp("chmod", path, func() error { return os.Chmod(path, perm) })
+}
+
+// WithStrictPerms extracts files with 0o600 and directories with 0o700 and
+// audits the result with CheckPermissions before returning, failing (and
+// cleaning up) if any entry is accessible to other users — for example
+// because a later WithFileMode widened the mode again.
+func WithStrictPerms() Option {
+	return func(o *options) {
+		o.fileMode = 0o600
+		o.dirMode = 0o700
+		o.strictPerms = true
+	}
+}
+
+// PermsProfile is a named permission set for WithPerms, so callers can state
+// intent instead of reasoning about octal modes on every platform.
+type PermsProfile int
+
+const (
+	// PermsStrict limits access to the current user (0o600 files, 0o700
+	// directories) and audits the result like WithStrictPerms. On Windows it
+	// also applies WithPrivateACL.
+	PermsStrict PermsProfile = iota
+	// PermsShared makes the tree readable by all users (0o644/0o755)
+	// regardless of the process umask.
+	PermsShared
+	// PermsExecutable is PermsShared with every file executable (0o755), for
+	// trees of bundled tools and scripts.
+	PermsExecutable
+)
+
+// WithPerms applies profile, with modes applied exactly (see WithExactPerms).
+// On Windows, where only the read-only attribute derives from the mode,
+// PermsStrict relies on an access control list instead (see WithPrivateACL).
+func WithPerms(profile PermsProfile) Option {
+	return func(o *options) {
+		o.exactPerms = true
+		switch profile {
+		case PermsStrict:
+			WithStrictPerms()(o)
+			o.privateACL = true
+		case PermsShared:
+			o.fileMode, o.dirMode = 0o644, 0o755
+		case PermsExecutable:
+			o.fileMode, o.dirMode = 0o755, 0o755
+			if runtime.GOOS == "windows" {
+				o.fileMode = 0o644 // No execute bits; avoid implying otherwise
+			}
+		}
+	}
+}
+
+// InsecurePermsError is returned by CheckPermissions when extracted entries
+// grant access to the group or to other users.
+type InsecurePermsError struct {
+	Paths []string // Offending paths, in walk order
+}
+
+func (e *InsecurePermsError) Error() string {
+	return fmt.Sprintf("%d entries accessible to other users (first: %s)", len(e.Paths), e.Paths[0])
+}
+
+// CheckPermissions walks dir and reports every file or directory (including
+// dir itself) whose mode grants any permission to the group or to other
+// users. It returns an *InsecurePermsError listing them, or nil if the tree is
+// private to its owner. Symlinks are not reported since their mode is not
+// used for access checks. On Windows, where mode bits do not reflect ACLs,
+// CheckPermissions always returns nil.
+func CheckPermissions(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	var insecure []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode().Perm()&0o077 != 0 {
+			insecure = append(insecure, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(insecure) > 0 {
+		return &InsecurePermsError{Paths: insecure}
+	}
+	return nil
+}
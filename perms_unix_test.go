@@ -0,0 +1,132 @@
+//go:build unix
+
+package efs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExactPermsIgnoreUmask(t *testing.T) {
+	old := syscall.Umask(0o077)
+	defer syscall.Umask(old)
+
+	mem := fstest.MapFS{"sub/a.txt": {Data: []byte("A")}}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		wantFile os.FileMode
+		wantDir  os.FileMode
+	}{
+		{"umask", nil, 0o600, 0o700},
+		{"exact", []Option{WithExactPerms()}, 0o644, 0o755},
+		{"exact custom", []Option{WithExactPerms(), WithFileMode(0o640), WithDirMode(0o750)}, 0o640, 0o750},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, cleanup, err := ExtractToTemp(mem, ".", "perms", t.TempDir(), tt.opts...)
+			if err != nil {
+				t.Fatalf("ExtractToTemp error: %v", err)
+			}
+			defer cleanup()
+
+			fi, err := os.Stat(filepath.Join(dir, "sub", "a.txt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fi.Mode().Perm(); got != tt.wantFile {
+				t.Errorf("file mode: expected %o, got %o", tt.wantFile, got)
+			}
+			di, err := os.Stat(filepath.Join(dir, "sub"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := di.Mode().Perm(); got != tt.wantDir {
+				t.Errorf("dir mode: expected %o, got %o", tt.wantDir, got)
+			}
+		})
+	}
+}
+
+func TestWithPerms(t *testing.T) {
+	old := syscall.Umask(0o077)
+	defer syscall.Umask(old)
+
+	mem := fstest.MapFS{"bin/tool": {Data: []byte("#!/bin/sh\n")}}
+
+	tests := []struct {
+		name     string
+		profile  PermsProfile
+		wantFile os.FileMode
+		wantDir  os.FileMode
+	}{
+		{"strict", PermsStrict, 0o600, 0o700},
+		{"shared", PermsShared, 0o644, 0o755},
+		{"executable", PermsExecutable, 0o755, 0o755},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, cleanup, err := ExtractToTemp(mem, ".", "perms", t.TempDir(), WithPerms(tt.profile))
+			if err != nil {
+				t.Fatalf("ExtractToTemp error: %v", err)
+			}
+			defer cleanup()
+
+			file := filepath.Join(dir, "bin", "tool")
+			if fi, err := os.Stat(file); err != nil || fi.Mode().Perm() != tt.wantFile {
+				t.Errorf("file mode: expected %o, got %v (err=%v)", tt.wantFile, fi, err)
+			}
+			if di, err := os.Stat(filepath.Join(dir, "bin")); err != nil || di.Mode().Perm() != tt.wantDir {
+				t.Errorf("dir mode: expected %o, got %v (err=%v)", tt.wantDir, di, err)
+			}
+			if tt.profile != PermsStrict {
+				return
+			}
+			if err := CheckPermissions(dir); err != nil {
+				t.Errorf("expected a private tree, got %v", err)
+			}
+			// A file widened after extraction no longer passes the audit.
+			if err := os.Chmod(file, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			var permErr *InsecurePermsError
+			if err := CheckPermissions(dir); !errors.As(err, &permErr) || len(permErr.Paths) != 1 || permErr.Paths[0] != file {
+				t.Errorf("expected %s to be flagged, got %v", file, err)
+			}
+		})
+	}
+
+	// PermsStrict refuses a tree a later option widens.
+	_, _, err := ExtractToTemp(mem, ".", "perms", t.TempDir(), WithPerms(PermsStrict), WithFileMode(0o644))
+	if permErr := (*InsecurePermsError)(nil); !errors.As(err, &permErr) {
+		t.Errorf("expected *InsecurePermsError, got %v", err)
+	}
+}
+
+func TestStrictPerms(t *testing.T) {
+	mem := fstest.MapFS{"sub/secret.txt": {Data: []byte("s3cr3t")}}
+
+	dir, cleanup, err := ExtractToTemp(mem, ".", "strict", t.TempDir(), WithStrictPerms())
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+	if err := CheckPermissions(dir); err != nil {
+		t.Fatalf("expected private tree, got %v", err)
+	}
+
+	// Widening the file mode after WithStrictPerms must be flagged.
+	_, _, err = ExtractToTemp(mem, ".", "strict", t.TempDir(), WithStrictPerms(), WithExactPerms(), WithFileMode(0o644))
+	var permErr *InsecurePermsError
+	if !errors.As(err, &permErr) {
+		t.Fatalf("expected *InsecurePermsError, got %v", err)
+	}
+	if len(permErr.Paths) != 1 || filepath.Base(permErr.Paths[0]) != "secret.txt" {
+		t.Errorf("expected secret.txt to be flagged, got %v", permErr.Paths)
+	}
+}
@@ -0,0 +1,148 @@
+package efs
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Plan is a prepared extraction: the entries to materialize, computed by
+// walking the source without touching disk. A Plan can be inspected (for
+// preview or approval workflows) and applied to any number of destinations.
+type Plan struct {
+	fsys    fs.FS
+	sources []source
+	o       *options
+	entries []planEntry
+}
+
+// PlanEntry describes one entry of a Plan.
+type PlanEntry struct {
+	Path   string // Slash-separated destination path relative to the extraction root
+	Source string // Path within the source fs.FS
+	IsDir  bool
+	Size   int64 // Source size in bytes; 0 for directories
+}
+
+// Prepare walks root in fsys with the given options and returns the resulting
+// Plan. Nothing is written until Apply. If root is empty, "." is used; root
+// handling otherwise matches ExtractToTemp.
+func Prepare(fsys fs.FS, root string, opts ...Option) (*Plan, error) {
+	if root == "" {
+		root = "."
+	}
+	o := newOptions(opts)
+	sources := []source{{root: root, dest: "."}}
+	entries, err := prepare(fsys, sources, o)
+	if err != nil {
+		return nil, err
+	}
+	return &Plan{fsys: fsys, sources: sources, o: o, entries: entries}, nil
+}
+
+// Entries returns the planned entries in extraction order.
+func (p *Plan) Entries() []PlanEntry {
+	out := make([]PlanEntry, len(p.entries))
+	for i, e := range p.entries {
+		out[i] = PlanEntry{Path: e.rel, Source: e.src, IsDir: e.d.IsDir(), Size: e.size}
+	}
+	return out
+}
+
+// Apply performs the plan into dst with the semantics of ExtractTo: dst is
+// created if needed and existing files are handled by WithConflict. File
+// contents are read from the source at apply time. Apply stops between
+// entries when ctx is done and returns ctx.Err().
+//
+// If Apply fails, every file and directory it created (including dst itself)
+// is removed again, while content that existed before is left alone. Files
+// that were overwritten keep their new content; use WithAtomic when the
+// previous tree must survive a failure intact. A Plan may be applied
+// repeatedly and to different destinations.
+func (p *Plan) Apply(ctx context.Context, dst string) (err error) {
+	start := time.Now()
+	o := p.o
+	defer func() { o.reportError(err, "apply", false) }()
+	absDst, absErr := filepath.Abs(dst)
+	if absErr != nil {
+		absDst = dst
+	}
+
+	if err := o.checkSpace(absDst, planSize(p.entries)); err != nil {
+		return err
+	}
+	if o.hasExecutables(p.entries) && noexecMount(existingDir(absDst)) {
+		return &DestError{Path: dst, Err: ErrNoExecMount}
+	}
+
+	target := absDst
+	var j *journal
+	if o.atomic {
+		staging, err := o.stagingDir(absDst)
+		if err != nil {
+			return &DestError{Path: dst, Err: fmt.Errorf("create staging dir: %w", err)}
+		}
+		target = staging
+	} else {
+		j = &journal{}
+		if err := o.retry(func() error { return j.mkdirAll(absDst, o.dirPerm()) }); err != nil {
+			j.rollback()
+			return &DestError{Path: dst, Err: fmt.Errorf("create destination: %w", err)}
+		}
+	}
+
+	var rep Report
+	err = p.applyInto(ctx, target, j, &rep)
+	if o.atomic {
+		if err == nil {
+			err = destErr(absDst, moveTree(target, absDst, o.dirPerm()))
+		}
+		if err != nil {
+			_ = os.RemoveAll(target)
+		}
+	} else if err != nil {
+		j.rollback()
+	} else if o.mirror {
+		err = o.prune(absDst, p.entries)
+	}
+	if err == nil && o.reportTo != nil {
+		rep.Duration = time.Since(start)
+		*o.reportTo = rep
+	}
+	return err
+}
+
+// applyInto materializes the plan in the existing directory dir, recording
+// created entries in j if it is non-nil, and accumulating what it wrote in
+// rep.
+func (p *Plan) applyInto(ctx context.Context, dir string, j *journal, rep *Report) error {
+	if p.o.metaFile {
+		j.create(filepath.Join(dir, MetaFileName))
+	}
+	if err := p.o.writeMeta(dir, "", p.sources); err != nil {
+		return destErr(dir, err)
+	}
+	if p.o.notices {
+		j.create(filepath.Join(dir, NoticesFileName))
+	}
+	if err := p.o.writeNotices(p.fsys, p.entries, dir); err != nil {
+		return err
+	}
+	a := &applier{fsys: p.fsys, o: p.o, rep: rep, j: j, limits: &softLimits{}, prog: p.o.newProgress(p.entries)}
+	if err := a.apply(ctx, p.entries, dir); err != nil {
+		return err
+	}
+	if p.o.strictPerms {
+		if err := CheckPermissions(dir); err != nil {
+			return err
+		}
+	}
+	if p.o.manifestFile {
+		j.create(filepath.Join(dir, ManifestFileName))
+	}
+	_, err := p.o.writeManifest(dir, p.entries)
+	return err
+}
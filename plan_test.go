@@ -0,0 +1,87 @@
+package efs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPrepareApply(t *testing.T) {
+	mem := fstest.MapFS{
+		"root/a.txt":     {Data: []byte("A")},
+		"root/sub/b.txt": {Data: []byte("BB")},
+	}
+
+	p, err := Prepare(mem, "root")
+	if err != nil {
+		t.Fatalf("Prepare error: %v", err)
+	}
+	entries := p.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %+v", entries)
+	}
+	if e := entries[2]; e.Path != "sub/b.txt" || e.Source != "root/sub/b.txt" || e.IsDir || e.Size != 2 {
+		t.Errorf("unexpected entry %+v", e)
+	}
+
+	// The same plan can be applied to several destinations.
+	for _, dst := range []string{t.TempDir(), filepath.Join(t.TempDir(), "fresh")} {
+		if err := p.Apply(context.Background(), dst); err != nil {
+			t.Fatalf("Apply(%s) error: %v", dst, err)
+		}
+		if data, err := os.ReadFile(filepath.Join(dst, "sub", "b.txt")); err != nil || string(data) != "BB" {
+			t.Errorf("expected sub/b.txt in %s, got %q, %v", dst, data, err)
+		}
+	}
+}
+
+func TestApplyCanceled(t *testing.T) {
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+	p, err := Prepare(mem, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	dst := t.TempDir()
+	if err := p.Apply(ctx, dst); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "a.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected nothing written after cancel, got err=%v", err)
+	}
+}
+
+func TestPrepareOrdered(t *testing.T) {
+	mem := fstest.MapFS{
+		"a/b.txt": {Data: []byte("B")},
+		"a-c.txt": {Data: []byte("C")},
+	}
+	paths := func(p *Plan) []string {
+		var out []string
+		for _, e := range p.Entries() {
+			out = append(out, e.Path)
+		}
+		return out
+	}
+
+	p, err := Prepare(mem, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := paths(p); !slices.Equal(got, []string{"a", "a/b.txt", "a-c.txt"}) {
+		t.Errorf("unexpected walk order %v", got)
+	}
+
+	p, err = Prepare(mem, ".", WithOrdered())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := paths(p); !slices.Equal(got, []string{"a", "a-c.txt", "a/b.txt"}) {
+		t.Errorf("unexpected lexical order %v", got)
+	}
+}
@@ -0,0 +1,176 @@
+package efs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"slices"
+	"sync"
+	"time"
+)
+
+// WithPriority extracts the entries matching one of globs (and the
+// directories containing them) first and lets Extract return as soon as they
+// are on disk, while the remaining entries are extracted in the background.
+// Applications such as games and desktop apps can thus show their launcher
+// UI before a large asset tree is complete. Use Extraction.WaitFor to block
+// until a particular entry is available and Extraction.Wait for the whole
+// tree. Patterns use the syntax of WithWindowsAttributes and are matched
+// against destination paths. Cleanup stops the background extraction.
+func WithPriority(globs ...string) Option {
+	return func(o *options) { o.priority = append(o.priority, globs...) }
+}
+
+// splitPriority partitions entries into those needed by the priority globs,
+// including their ancestor directories, and the rest. Both keep their order.
+func splitPriority(entries []planEntry, globs []string) (first, rest []planEntry) {
+	needed := make(map[string]bool)
+	for _, e := range entries {
+		if !matchAny(globs, e.rel) {
+			continue
+		}
+		for rel := e.rel; rel != "." && !needed[rel]; rel = path.Dir(rel) {
+			needed[rel] = true
+		}
+	}
+	for _, e := range entries {
+		if needed[e.rel] {
+			first = append(first, e)
+		} else {
+			rest = append(rest, e)
+		}
+	}
+	return first, rest
+}
+
+// background tracks the entries WithPriority left for later.
+type background struct {
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	changed *sync.Cond      // Broadcast when an entry completes or the run ends
+	pending map[string]bool // Entries not extracted yet
+	paused  bool            // Set by Pause; checked between entries
+	done    bool
+	err     error
+}
+
+// startBackground extracts rest into the extraction in a new goroutine;
+// start is when the extraction began, for the report.
+func (e *Extraction) startBackground(rest []planEntry, start time.Time) {
+	ctx, cancel := context.WithCancel(context.Background())
+	bg := &background{cancel: cancel, pending: make(map[string]bool, len(rest))}
+	bg.changed = sync.NewCond(&bg.mu)
+	for _, entry := range rest {
+		bg.pending[entry.rel] = true
+	}
+	e.bg = bg
+
+	go func() {
+		defer cancel()
+		var rep Report
+		a := &applier{fsys: e.fsys, o: e.o, rep: &rep, limits: &e.limits, prog: e.prog, hold: bg.hold, done: func(rel string) {
+			bg.mu.Lock()
+			delete(bg.pending, rel)
+			bg.mu.Unlock()
+			bg.changed.Broadcast()
+		}}
+		err := a.apply(ctx, rest, e.dir)
+		if err == nil && e.o.strictPerms {
+			err = CheckPermissions(e.dir)
+		}
+		var m *Manifest
+		if err == nil {
+			m, err = e.o.writeManifest(e.dir, e.entries)
+		}
+		if !errors.Is(err, context.Canceled) {
+			e.o.reportError(err, "extract", false)
+		}
+
+		e.mu.Lock()
+		if e.o.immutable {
+			e.release = append(e.release, func() error {
+				clearImmutable(a.written)
+				return nil
+			})
+		}
+		e.report.Files += rep.Files
+		e.report.Dirs += rep.Dirs
+		e.report.Bytes += rep.Bytes
+		e.report.Symlinks += rep.Symlinks
+		e.report.Skipped = append(e.report.Skipped, rep.Skipped...)
+		e.report.Failed = append(e.report.Failed, rep.Failed...)
+		e.report.DiskBytes, e.report.Inodes, _ = DiskUsage(e.dir)
+		e.report.Duration = time.Since(start)
+		e.manifest = m
+		e.mu.Unlock()
+
+		bg.mu.Lock()
+		bg.done, bg.err = true, err
+		bg.mu.Unlock()
+		bg.changed.Broadcast()
+	}()
+}
+
+// Wait blocks until the background part of a WithPriority extraction has
+// finished and returns its error, if any. The report then covers the whole
+// tree. Without WithPriority, Wait returns nil at once.
+func (e *Extraction) Wait() error {
+	bg := e.bg
+	if bg == nil {
+		return nil
+	}
+	bg.mu.Lock()
+	defer bg.mu.Unlock()
+	for !bg.done {
+		bg.changed.Wait()
+	}
+	return bg.err
+}
+
+// WaitFor blocks until the entry name (a slash-separated destination path,
+// as for Path) has been extracted. It returns nil at once for entries that
+// were extracted before Extract returned. If the background extraction fails
+// or is stopped by Cleanup before name is written, its error is returned;
+// names that are not part of the extraction report fs.ErrNotExist.
+func (e *Extraction) WaitFor(name string) error {
+	if !slices.ContainsFunc(e.entries, func(entry planEntry) bool { return entry.rel == name }) {
+		return &fs.PathError{Op: "wait", Path: name, Err: fs.ErrNotExist}
+	}
+	bg := e.bg
+	if bg == nil {
+		return nil
+	}
+	bg.mu.Lock()
+	defer bg.mu.Unlock()
+	for bg.pending[name] && !bg.done {
+		bg.changed.Wait()
+	}
+	if bg.pending[name] {
+		return bg.err
+	}
+	return nil
+}
+
+// pending reports whether rel is still waiting for the background extraction.
+func (e *Extraction) pending(rel string) bool {
+	bg := e.bg
+	if bg == nil {
+		return false
+	}
+	bg.mu.Lock()
+	defer bg.mu.Unlock()
+	return bg.pending[rel]
+}
+
+// stopBackground cancels a running background extraction and waits for it.
+func (e *Extraction) stopBackground() {
+	if bg := e.bg; bg != nil {
+		bg.cancel()
+		bg.mu.Lock()
+		bg.paused = false
+		bg.mu.Unlock()
+		bg.changed.Broadcast()
+		_ = e.Wait()
+	}
+}
@@ -0,0 +1,87 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitPriority(t *testing.T) {
+	mem := fstest.MapFS{
+		"a.txt":         {Data: []byte("A")},
+		"ui/logo.png":   {Data: []byte("L")},
+		"ui/menu.json":  {Data: []byte("M")},
+		"levels/1.dat":  {Data: []byte("1")},
+		"levels/ui.txt": {Data: []byte("U")},
+	}
+	entries, err := prepare(mem, []source{{root: ".", dest: "."}}, newOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, rest := splitPriority(entries, []string{"ui/*"})
+	var got []string
+	for _, e := range first {
+		got = append(got, e.rel)
+	}
+	if !slices.Equal(got, []string{"ui", "ui/logo.png", "ui/menu.json"}) {
+		t.Errorf("unexpected priority entries %v", got)
+	}
+	if len(first)+len(rest) != len(entries) {
+		t.Errorf("expected %d entries in total, got %d", len(entries), len(first)+len(rest))
+	}
+}
+
+func TestWithPriority(t *testing.T) {
+	mem := fstest.MapFS{
+		"root/ui/launcher.html": {Data: []byte("L")},
+		"root/data/big.bin":     {Data: make([]byte, 1<<10)},
+		"root/data/more.bin":    {Data: make([]byte, 1<<10)},
+	}
+	ex, err := Extract(mem, "root", "prio", t.TempDir(), WithPriority("ui/**"))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	if _, err := os.Stat(ex.Path("ui/launcher.html")); err != nil {
+		t.Fatalf("expected priority entry before Extract returns, got %v", err)
+	}
+	if err := ex.WaitFor("data/big.bin"); err != nil {
+		t.Fatalf("WaitFor error: %v", err)
+	}
+	if _, err := os.Stat(ex.Path("data/big.bin")); err != nil {
+		t.Fatalf("expected data/big.bin after WaitFor, got %v", err)
+	}
+	if err := ex.WaitFor("nope"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected ErrNotExist for unknown entry, got %v", err)
+	}
+	if err := ex.Wait(); err != nil {
+		t.Fatalf("Wait error: %v", err)
+	}
+	if rep := ex.Report(); rep.Files != 3 || rep.Dirs != 2 {
+		t.Errorf("expected report for the whole tree, got %+v", rep)
+	}
+	if err := ex.Verify(); err != nil {
+		t.Errorf("Verify error: %v", err)
+	}
+}
+
+func TestWithPriorityCleanupStops(t *testing.T) {
+	mem := fstest.MapFS{"root/first.txt": {Data: []byte("F")}}
+	for i := range 200 {
+		mem["root/bulk/"+string(rune('a'+i%26))+string(rune('a'+i/26))+".bin"] = &fstest.MapFile{Data: []byte("x")}
+	}
+	ex, err := Extract(mem, "root", "prio", t.TempDir(), WithPriority("first.txt"))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	if err := ex.Cleanup(); err != nil {
+		t.Fatalf("Cleanup error: %v", err)
+	}
+	if _, err := os.Stat(ex.Dir()); !os.IsNotExist(err) {
+		t.Errorf("expected directory removed after Cleanup, got %v", err)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package efs
+
+// processAlive reports whether a process with the given PID is running.
+// Without a way to tell, every process counts as running.
+func processAlive(pid int) bool {
+	return true
+}
@@ -0,0 +1,17 @@
+package efs
+
+import "os"
+
+// processAlive reports whether a process with the given PID is running.
+// FindProcess opens the process on Windows and fails if there is none.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
@@ -0,0 +1,107 @@
+package efs
+
+import (
+	"io"
+	"sync"
+)
+
+// Progress describes how far an extraction has come; see WithProgress.
+type Progress struct {
+	Path       string // File being written or just finished, relative to the extraction root; see WithRedaction
+	Files      int    // Files (non-directory entries) finished, including skipped and failed ones
+	TotalFiles int    // Files in the extraction
+	Bytes      int64  // Bytes written so far; finished files count with their source size
+	TotalBytes int64  // Size of all files as reported by the source
+}
+
+// WithProgress calls fn as an extraction advances: repeatedly while a file
+// is being written, and once each file is finished, so CLIs can render a
+// progress bar when unpacking large trees. Totals come from the walk done
+// before anything is written. Calls are serialized, also with
+// WithConcurrency, and fn should return quickly. For WithPriority the
+// progress covers the background part too. It applies to directory
+// extractions such as ExtractToTemp, ExtractTo and Plan.Apply.
+func WithProgress(fn func(Progress)) Option {
+	return func(o *options) { o.progress = fn }
+}
+
+// progress tracks the Progress of one extraction.
+type progress struct {
+	fn      func(Progress)
+	display func(name string) string // Redacts Path, see WithRedaction
+	mu      sync.Mutex
+	p       Progress
+	written map[string]int64 // Bytes written so far per unfinished file
+}
+
+// newProgress returns a tracker for entries, or nil without WithProgress.
+func (o *options) newProgress(entries []planEntry) *progress {
+	if o.progress == nil {
+		return nil
+	}
+	t := &progress{fn: o.progress, display: o.display, written: make(map[string]int64)}
+	for _, e := range entries {
+		if !e.d.IsDir() {
+			t.p.TotalFiles++
+			t.p.TotalBytes += e.size
+		}
+	}
+	return t
+}
+
+// writer returns w counting what is written to the file rel.
+func (t *progress) writer(rel string, w io.Writer) io.Writer {
+	if t == nil {
+		return w
+	}
+	return &progressWriter{t: t, rel: rel, name: t.display(rel), w: w}
+}
+
+// restart discards the bytes counted for the file rel so far, before a
+// WithRetry attempt writes it again.
+func (t *progress) restart(rel string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.p.Bytes -= t.written[rel]
+	delete(t.written, rel)
+}
+
+// finish records that the file entry e is done, whether it was written,
+// skipped or failed.
+func (t *progress) finish(e planEntry) {
+	if t == nil || e.d.IsDir() {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.p.Bytes += e.size - t.written[e.rel]
+	delete(t.written, e.rel)
+	t.p.Files++
+	t.p.Path = t.display(e.rel)
+	t.fn(t.p)
+}
+
+// progressWriter reports the bytes written through it.
+type progressWriter struct {
+	t    *progress
+	rel  string
+	name string // rel as reported in Progress.Path
+	w    io.Writer
+}
+
+func (pw *progressWriter) Write(b []byte) (int, error) {
+	n, err := pw.w.Write(b)
+	if n > 0 {
+		t := pw.t
+		t.mu.Lock()
+		t.written[pw.rel] += int64(n)
+		t.p.Bytes += int64(n)
+		t.p.Path = pw.name
+		t.fn(t.p)
+		t.mu.Unlock()
+	}
+	return n, err
+}
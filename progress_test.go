@@ -0,0 +1,72 @@
+package efs
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithProgress(t *testing.T) {
+	mem := fstest.MapFS{
+		"assets/big.bin":   {Data: bytes.Repeat([]byte("x"), 3*copyBufSize)},
+		"assets/a/b.txt":   {Data: []byte("B")},
+		"assets/a/c/d.txt": {Data: []byte("DD")},
+	}
+	total := int64(3*copyBufSize + 3)
+
+	for _, concurrency := range []int{1, 4} {
+		var events []Progress
+		e, err := Extract(mem, "assets", "progress", t.TempDir(), WithConcurrency(concurrency),
+			WithProgress(func(p Progress) { events = append(events, p) }))
+		if err != nil {
+			t.Fatalf("Extract error: %v", err)
+		}
+		e.Cleanup()
+
+		if len(events) < 5 {
+			t.Fatalf("expected chunk and file events, got %+v", events)
+		}
+		for i, p := range events {
+			if p.TotalFiles != 3 || p.TotalBytes != total || p.Bytes > total || i > 0 && p.Bytes < events[i-1].Bytes {
+				t.Errorf("unexpected event %d: %+v", i, p)
+			}
+		}
+		if last := events[len(events)-1]; last.Files != 3 || last.Bytes != total {
+			t.Errorf("expected a final event with everything done, got %+v", last)
+		}
+	}
+
+	var last Progress
+	p, err := Prepare(mem, "assets", WithProgress(func(p Progress) { last = p }))
+	if err != nil {
+		t.Fatalf("Prepare error: %v", err)
+	}
+	if err := p.Apply(context.Background(), t.TempDir()); err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+	if last.Files != 3 || last.Bytes != total {
+		t.Errorf("expected Apply to report progress, got %+v", last)
+	}
+}
+
+func TestProgressRedaction(t *testing.T) {
+	mem := fstest.MapFS{"secret-customer.txt": {Data: []byte("S")}}
+
+	var paths []string
+	e, err := Extract(mem, ".", "progress", t.TempDir(), WithRedaction(HashName),
+		WithProgress(func(p Progress) { paths = append(paths, p.Path) }))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	e.Cleanup()
+
+	if len(paths) == 0 {
+		t.Fatal("expected progress events")
+	}
+	for _, p := range paths {
+		if p != HashName("secret-customer.txt") {
+			t.Errorf("expected redacted path, got %q", p)
+		}
+	}
+}
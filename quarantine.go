@@ -0,0 +1,11 @@
+package efs
+
+// WithClearQuarantine removes the com.apple.quarantine attribute from
+// extracted files on macOS. Processes started from a downloaded app can have
+// their writes quarantined, and Gatekeeper then refuses to run bundled helper
+// tools extracted at runtime. Clearing is best-effort: files without the
+// attribute are left alone and failures are ignored. It relies on
+// /usr/bin/xattr and is a no-op on other platforms.
+func WithClearQuarantine() Option {
+	return func(o *options) { o.clearQuarantine = true }
+}
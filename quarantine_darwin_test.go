@@ -0,0 +1,28 @@
+package efs
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithClearQuarantine(t *testing.T) {
+	mem := fstest.MapFS{"bin/tool": {Data: []byte("#!/bin/sh\n")}}
+	dir := t.TempDir()
+	tool := filepath.Join(dir, "tool")
+
+	if err := ExtractTo(mem, "bin", dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command("/usr/bin/xattr", "-w", "com.apple.quarantine", "0081;00000000;test;", tool).Run(); err != nil {
+		t.Skipf("cannot set quarantine attribute: %v", err)
+	}
+
+	if err := ExtractTo(mem, "bin", dir, WithClearQuarantine()); err != nil {
+		t.Fatalf("ExtractTo error: %v", err)
+	}
+	if err := exec.Command("/usr/bin/xattr", "-p", "com.apple.quarantine", tool).Run(); err == nil {
+		t.Error("expected quarantine attribute to be removed")
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !darwin
+
+package efs
+
+// clearQuarantine is a no-op outside macOS.
+func clearQuarantine(paths []string) {}
@@ -0,0 +1,110 @@
+package efs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithRedaction rewrites source file names with fn wherever efs reports them:
+// error messages, and any logs or events emitted during extraction. Use it
+// when asset names themselves are sensitive (customer identifiers, unreleased
+// product names). HashName is a ready-made redactor. Redacted errors keep
+// their original error chain, so errors.Is and errors.As continue to work.
+func WithRedaction(fn func(name string) string) Option {
+	return func(o *options) { o.redact = fn }
+}
+
+// HashName is a redactor for WithRedaction that replaces a name with a short,
+// stable SHA-256 fingerprint such as "sha256:3f79bb7b435b". Equal names map to
+// equal fingerprints, so redacted logs can still be correlated.
+func HashName(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return "sha256:" + hex.EncodeToString(sum[:6])
+}
+
+// display returns name as it may be shown in logs, events and errors.
+func (o *options) display(name string) string {
+	if o.redact == nil {
+		return name
+	}
+	return o.redact(name)
+}
+
+// redactErr scrubs the given source names from err's message. names are
+// slash-separated source paths; their native-separator forms are scrubbed too
+// since they appear in destination paths.
+func (o *options) redactErr(err error, names ...string) error {
+	if err == nil || o.redact == nil {
+		return err
+	}
+	return &redactedError{msg: o.scrub(err.Error(), names...), err: err}
+}
+
+// scrub replaces every occurrence of names in s with their redacted form.
+// Only whole names are replaced, bounded by path separators, quotes,
+// punctuation or white space, so a short name such as "a" leaves words and
+// other path components alone.
+func (o *options) scrub(s string, names ...string) string {
+	if o.redact == nil {
+		return s
+	}
+	// Replace longer names first so "a/b.txt" is not half-replaced via "a".
+	sorted := append([]string(nil), names...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	for _, name := range sorted {
+		if name == "" || name == "." {
+			continue
+		}
+		r := o.redact(name)
+		s = replaceWhole(s, name, r)
+		if native := filepath.FromSlash(name); native != name {
+			s = replaceWhole(s, native, r)
+		}
+	}
+	return s
+}
+
+// replaceWhole replaces the occurrences of old in s that are not part of a
+// longer name.
+func replaceWhole(s, old, new string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, old)
+		if i < 0 {
+			break
+		}
+		end := i + len(old)
+		before, _ := utf8.DecodeLastRuneInString(s[:i])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if (i == 0 || nameBoundary(before)) && (end == len(s) || nameBoundary(after)) {
+			b.WriteString(s[:i])
+			b.WriteString(new)
+		} else {
+			b.WriteString(s[:end])
+		}
+		s = s[end:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// nameBoundary reports whether r can delimit a path or path component in a
+// message.
+func nameBoundary(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("/\\\"'`:;,()[]{}<>=", r)
+}
+
+// redactedError carries a scrubbed message while preserving the original chain.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
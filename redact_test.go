@@ -0,0 +1,60 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRedactionScrubsErrors(t *testing.T) {
+	mem := fstest.MapFS{"public.txt": {Data: []byte("ok")}}
+
+	_, _, err := ExtractFile(mem, "customers/acme-corp.json", "cfg", t.TempDir(), WithRedaction(HashName))
+	if err == nil {
+		t.Fatal("expected error for missing file")
+	}
+	if strings.Contains(err.Error(), "acme-corp") {
+		t.Errorf("error leaks file name: %v", err)
+	}
+	if !strings.Contains(err.Error(), HashName("customers/acme-corp.json")) {
+		t.Errorf("expected hashed name in error, got: %v", err)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected errors.Is(err, fs.ErrNotExist), got %v", err)
+	}
+
+	bad := badFS{base: fstest.MapFS{"acme-corp/a.txt": {Data: []byte("A")}}, fail: "acme-corp/a.txt"}
+	_, _, err = ExtractToTemp(bad, ".", "tst", t.TempDir(), WithRedaction(HashName))
+	if err == nil || strings.Contains(err.Error(), "acme-corp") {
+		t.Errorf("expected redacted error, got: %v", err)
+	}
+}
+
+func TestRedactionWholeNames(t *testing.T) {
+	o := newOptions([]Option{WithRedaction(func(string) string { return "<x>" })})
+
+	// A one-letter name is replaced where it stands alone, not inside words,
+	// longer names or other path components.
+	got := o.scrub(`open a: cannot read "/tmp/efs-1/a" after data/a.bin failed`, "a")
+	want := `open <x>: cannot read "/tmp/efs-1/<x>" after data/a.bin failed`
+	if got != want {
+		t.Errorf("scrub = %q, want %q", got, want)
+	}
+
+	bad := badFS{base: fstest.MapFS{"a": {Data: []byte("A")}}, fail: "a"}
+	_, _, err := ExtractToTemp(bad, ".", "tst", t.TempDir(), WithRedaction(HashName))
+	if err == nil || !strings.Contains(err.Error(), "read source: forced open error") {
+		t.Errorf("expected the message intact, got: %v", err)
+	}
+}
+
+func TestHashNameStable(t *testing.T) {
+	if HashName("a.txt") != HashName("a.txt") {
+		t.Error("HashName must be deterministic")
+	}
+	if HashName("a.txt") == HashName("b.txt") {
+		t.Error("HashName must distinguish names")
+	}
+}
@@ -0,0 +1,70 @@
+package efs
+
+import (
+	"fmt"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+)
+
+// Refresh re-extracts the file name (a slash-separated destination path, as
+// for Path) from the source, replacing the current copy, for example to let
+// users reset a bundled configuration file to its default. The new content
+// is written to a temporary file next to it and renamed into place, so
+// readers see either the old or the new file, never a partial one. Missing
+// parent directories are recreated. Directories, preserved symlinks and
+// names that were not part of the extraction are rejected with an
+// *fs.PathError. With WithPriority, Refresh first waits for name.
+func (e *Extraction) Refresh(name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "refresh", Path: name, Err: fs.ErrInvalid}
+	}
+	i := slices.IndexFunc(e.entries, func(entry planEntry) bool { return entry.rel == name })
+	if i < 0 {
+		return &fs.PathError{Op: "refresh", Path: name, Err: fs.ErrNotExist}
+	}
+	entry := e.entries[i]
+	if entry.d.IsDir() || e.o.isSymlink(e.fsys, entry.d) || e.o.isReparse(e.fsys, entry.d) {
+		return &fs.PathError{Op: "refresh", Path: name, Err: fmt.Errorf("not a regular file: %w", fs.ErrInvalid)}
+	}
+	if err := e.WaitFor(name); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.done {
+		return &DestError{Path: e.dir, Err: fmt.Errorf("refresh: %w", fs.ErrNotExist)}
+	}
+	a := &applier{fsys: e.fsys, o: e.o, rep: &Report{}, root: e.dir}
+	return e.o.redactErr(a.refreshFile(entry), entry.src, entry.rel)
+}
+
+// refreshFile atomically replaces the extracted copy of entry.
+func (a *applier) refreshFile(entry planEntry) error {
+	o := a.o
+	dst := a.dest(entry.rel)
+	if err := a.mkdirAll(filepath.Dir(dst)); err != nil {
+		return destErr(dst, err)
+	}
+
+	tmp := dst + ".efs-refresh-" + strconv.FormatUint(uint64(rand.Uint32()), 10)
+	if _, _, err := a.writeFile(entry.src, entry.rel, tmp); err != nil {
+		os.Remove(tmp)
+		return destErr(tmp, err)
+	}
+	if o.immutable {
+		clearImmutable([]string{dst})
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return destErr(dst, err)
+	}
+	if o.immutable {
+		return destErr(dst, makeImmutable([]string{dst}))
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRefresh(t *testing.T) {
+	mem := fstest.MapFS{
+		"cfg/app.toml":      {Data: []byte("default = true\n")},
+		"cfg/themes/a.json": {Data: []byte("{}")},
+	}
+	ex, err := Extract(mem, "cfg", "refresh", t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	if err := os.WriteFile(ex.Path("app.toml"), []byte("default = false\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(ex.Path("themes")); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"app.toml", "themes/a.json"} {
+		if err := ex.Refresh(name); err != nil {
+			t.Fatalf("Refresh(%s) error: %v", name, err)
+		}
+	}
+	if data, _ := os.ReadFile(ex.Path("app.toml")); string(data) != "default = true\n" {
+		t.Errorf("expected default content, got %q", data)
+	}
+	if err := ex.Verify(); err != nil {
+		t.Errorf("Verify after Refresh: %v", err)
+	}
+
+	for name, want := range map[string]error{"themes": fs.ErrInvalid, "nope.txt": fs.ErrNotExist, "../x": fs.ErrInvalid} {
+		if err := ex.Refresh(name); !errors.Is(err, want) {
+			t.Errorf("Refresh(%s): expected %v, got %v", name, want, err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package efs
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// WithRename calls rename for every walked entry with the slash-separated
+// path it would have below the extraction root, and extracts the entry to
+// the returned dstRel instead, or leaves it out if skip is true. This remaps
+// embedded paths on the way out, e.g. dropping a version directory or
+// turning "config.tmpl" into "config.yaml", without post-processing the
+// extracted tree.
+//
+// rename is called for directories too and sees every path as it is in the
+// source, not as renamed by earlier calls, so it must map a directory and
+// its contents consistently. A directory mapped to "." is not created but
+// its contents are still walked; a skipped directory is left out with
+// everything below it. dstRel must be a valid fs.FS path (see
+// fs.ValidPath), and two files may not be mapped to the same path.
+// WithInclude, WithExclude and WithFilter see the original paths.
+func WithRename(rename func(srcPath string) (dstRel string, skip bool)) Option {
+	return func(o *options) { o.rename = rename }
+}
+
+// renamed applies WithRename to the entry d at destination path rel. It
+// returns the new path, or "" if the entry is left out, with fs.SkipDir for
+// a skipped directory.
+func (o *options) renamed(rel string, d fs.DirEntry) (string, error) {
+	if o.rename == nil {
+		return rel, nil
+	}
+	dst, skip := o.rename(rel)
+	switch {
+	case skip && d.IsDir():
+		return "", fs.SkipDir
+	case skip:
+		return "", nil
+	case !fs.ValidPath(dst):
+		return "", fmt.Errorf("rename %s: invalid destination %q", rel, dst)
+	case dst == "." && !d.IsDir():
+		return "", fmt.Errorf("rename %s: a file cannot become the extraction root", rel)
+	case dst == ".":
+		return "", nil // Not created, but its contents are walked
+	}
+	return dst, nil
+}
@@ -0,0 +1,62 @@
+package efs
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithRename(t *testing.T) {
+	mem := fstest.MapFS{
+		"assets/v1.2/config.tmpl":  {Data: []byte("cfg")},
+		"assets/v1.2/bin/tool":     {Data: []byte("bin")},
+		"assets/v1.2/docs/a.txt":   {Data: []byte("doc")},
+		"assets/v1.2/docs/b.txt":   {Data: []byte("doc")},
+		"assets/v1.2/internal.txt": {Data: []byte("int")},
+	}
+	rename := func(p string) (string, bool) {
+		if p == "v1.2/docs" || p == "v1.2/internal.txt" {
+			return "", true
+		}
+		if p == "v1.2" {
+			return ".", false
+		}
+		p = strings.TrimPrefix(p, "v1.2/")
+		if p == "config.tmpl" {
+			return "config.yaml", false
+		}
+		return p, false
+	}
+
+	e, err := Extract(mem, "assets", "rename", t.TempDir(), WithRename(rename))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+	for name, want := range map[string]string{"config.yaml": "cfg", "bin/tool": "bin"} {
+		if data, err := os.ReadFile(e.Path(name)); err != nil || string(data) != want {
+			t.Errorf("expected %s to hold %q, got %q (%v)", name, want, data, err)
+		}
+	}
+	for _, name := range []string{"v1.2", "docs", "internal.txt", "config.tmpl"} {
+		if _, err := os.Stat(e.Path(name)); err == nil {
+			t.Errorf("expected %s to be left out", name)
+		}
+	}
+	if r := e.Report(); r.Files != 2 || r.Dirs != 1 {
+		t.Errorf("unexpected report %+v", r)
+	}
+	if err := e.Verify(); err != nil {
+		t.Errorf("Verify error: %v", err)
+	}
+
+	for _, bad := range []func(string) (string, bool){
+		func(p string) (string, bool) { return "../" + p, false },
+		func(p string) (string, bool) { return "same", false },
+	} {
+		if _, err := Extract(mem, "assets", "rename", t.TempDir(), WithRename(bad)); err == nil {
+			t.Error("expected an error for an invalid or conflicting destination")
+		}
+	}
+}
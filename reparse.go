@@ -0,0 +1,78 @@
+package efs
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// ReparsePolicy decides how WithReparsePoints treats Windows reparse points
+// in the source other than symlinks: directory junctions (mount points) and
+// special files such as cloud placeholders. Go reports them with
+// fs.ModeIrregular set.
+type ReparsePolicy int
+
+const (
+	ReparseFollow   ReparsePolicy = iota // Extract what the reparse point resolves to, like a plain directory or file (default)
+	ReparseJunction                      // Recreate directory junctions as junctions to the same target; follow other reparse points
+	ReparseSkip                          // Leave reparse points out and list them in Report.Skipped
+)
+
+// WithReparsePoints sets the policy for reparse points in sources such as
+// DirFS on Windows. By default their content is extracted, so a junction to
+// a large directory is copied in full. ReparseJunction needs a source that
+// implements SymlinkFS, as DirFS does, to read junction targets; with other
+// sources junctions are followed. Other platforms have no reparse points,
+// so the policy only matters for sources that report fs.ModeIrregular.
+func WithReparsePoints(policy ReparsePolicy) Option {
+	return func(o *options) { o.reparse = policy }
+}
+
+// isReparse reports whether d is a reparse point that the policy handles
+// instead of extracting it as usual.
+func (o *options) isReparse(fsys fs.FS, d fs.DirEntry) bool {
+	if d.Type()&fs.ModeIrregular == 0 {
+		return false
+	}
+	switch o.reparse {
+	case ReparseSkip:
+		return true
+	case ReparseJunction:
+		_, ok := fsys.(SymlinkFS)
+		return ok && d.IsDir()
+	}
+	return false
+}
+
+// extractReparse applies the reparse point policy to src.
+func (a *applier) extractReparse(src, rel, dst string) error {
+	o := a.o
+	if o.reparse == ReparseSkip {
+		a.rep.Skipped = append(a.rep.Skipped, rel)
+		return nil
+	}
+	target, err := a.fsys.(SymlinkFS).ReadLink(src)
+	if err != nil {
+		return sourceErr(src, err)
+	}
+	if err := a.mkdirAll(filepath.Dir(dst)); err != nil {
+		return destErr(dst, err)
+	}
+	skip, err := o.resolveConflict(dst)
+	if err != nil {
+		return destErr(dst, err)
+	}
+	if skip {
+		a.rep.Skipped = append(a.rep.Skipped, rel)
+		return nil
+	}
+	abs := filepath.FromSlash(target)
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(filepath.Dir(dst), abs)
+	}
+	a.j.create(dst)
+	if err := createJunction(abs, dst); err != nil {
+		return destErr(dst, err)
+	}
+	a.rep.Symlinks++
+	return nil
+}
@@ -0,0 +1,48 @@
+package efs
+
+import (
+	"io/fs"
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReparsePoints(t *testing.T) {
+	// MapFS stands in for a Windows source: a junction reports as a directory
+	// with ModeIrregular, a cloud placeholder as an irregular file.
+	mem := fstest.MapFS{
+		"root/a.txt":          {Data: []byte("A")},
+		"root/junction":       {Mode: fs.ModeDir | fs.ModeIrregular},
+		"root/junction/b.txt": {Data: []byte("B")},
+		"root/placeholder":    {Data: []byte("P"), Mode: fs.ModeIrregular},
+	}
+
+	ex, err := Extract(mem, "root", "reparse", t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+	for _, name := range []string{"junction/b.txt", "placeholder"} {
+		if _, err := os.Stat(ex.Path(name)); err != nil {
+			t.Errorf("expected %s to be followed by default, got %v", name, err)
+		}
+	}
+
+	ex, err = Extract(mem, "root", "reparse", t.TempDir(), WithReparsePoints(ReparseSkip))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+	if got := ex.Report().Skipped; !slices.Equal(got, []string{"junction", "placeholder"}) {
+		t.Errorf("expected reparse points reported as skipped, got %v", got)
+	}
+	for _, name := range []string{"junction", "placeholder"} {
+		if _, err := os.Lstat(ex.Path(name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be skipped, got %v", name, err)
+		}
+	}
+	if err := ex.Verify(); err != nil {
+		t.Errorf("expected skipped reparse points to verify, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReparseJunction(t *testing.T) {
+	target := t.TempDir()
+	os.WriteFile(filepath.Join(target, "big.bin"), []byte("content"), 0o644)
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "a.txt"), []byte("A"), 0o644)
+	if err := createJunction(target, filepath.Join(src, "data")); err != nil {
+		t.Skipf("cannot create junction: %v", err)
+	}
+
+	ex, err := Extract(DirFS(src), ".", "junction", t.TempDir(), WithReparsePoints(ReparseJunction))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+	got, err := os.Readlink(ex.Path("data"))
+	if err != nil || filepath.Clean(got) != filepath.Clean(target) {
+		t.Fatalf("expected junction to %s, got %q, %v", target, got, err)
+	}
+	if ex.Report().Symlinks != 1 {
+		t.Errorf("expected one recreated junction, got %+v", ex.Report())
+	}
+	if err := ex.Verify(); err != nil {
+		t.Errorf("expected junction to verify, got %v", err)
+	}
+}
+
+func TestCreateJunctionMetacharacters(t *testing.T) {
+	// cmd.exe would run "echo" and fail on the quote-free remainder.
+	target := filepath.Join(t.TempDir(), "a&echo pwned^ (x) %PATH%")
+	if err := os.Mkdir(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(target, "f.txt"), []byte("F"), 0o644)
+	link := filepath.Join(t.TempDir(), "b&c")
+	if err := createJunction(target, link); err != nil {
+		t.Fatalf("createJunction error: %v", err)
+	}
+	if got, err := os.Readlink(link); err != nil || filepath.Clean(got) != filepath.Clean(target) {
+		t.Fatalf("expected junction to %s, got %q, %v", target, got, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(link, "f.txt")); err != nil || string(data) != "F" {
+		t.Errorf("expected to read through the junction, got %q, %v", data, err)
+	}
+}
@@ -0,0 +1,44 @@
+package efs
+
+import (
+	"math"
+	"time"
+)
+
+// maxBackoffShift caps the doubling of the WithRetry backoff.
+const maxBackoffShift = 16
+
+// WithRetry retries an individual file write or directory creation up to n
+// more times when it fails with a transient condition (EINTR, EBUSY, Windows
+// sharing violations, stale NFS handles, dropped SMB connections on UNC paths
+// and the like) instead of failing the whole extraction on the first blip.
+// The wait before retry i (starting at 0) is backoff << i, doubling at most
+// 16 times. Errors that are not transient are returned immediately. Retries
+// apply to directory extractions such as ExtractToTemp and ExtractTo.
+func WithRetry(n int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.retries = max(n, 0)
+		o.backoff = backoff
+	}
+}
+
+// retry runs op and repeats it according to WithRetry while it fails with a
+// transient error. The last error is returned.
+func (o *options) retry(op func() error) error {
+	err := op()
+	for i := 0; i < o.retries && err != nil && isTransient(err); i++ {
+		time.Sleep(o.retryDelay(i))
+		err = op()
+	}
+	return err
+}
+
+// retryDelay returns the wait before retry i, saturating instead of
+// overflowing for large backoffs.
+func (o *options) retryDelay(i int) time.Duration {
+	shift := min(i, maxBackoffShift)
+	if o.backoff > math.MaxInt64>>shift {
+		return math.MaxInt64
+	}
+	return o.backoff << shift
+}
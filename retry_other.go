@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package efs
+
+// isTransient reports whether err is a condition worth retrying; no
+// conditions are known on this platform.
+func isTransient(err error) bool {
+	return false
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package efs
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isTransient reports whether err is a condition worth retrying.
+func isTransient(err error) bool {
+	for _, errno := range []syscall.Errno{syscall.EINTR, syscall.EAGAIN, syscall.EBUSY, syscall.ETXTBSY, syscall.ESTALE} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+//go:build unix
+
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"syscall"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestRetryTransient(t *testing.T) {
+	o := newOptions([]Option{WithRetry(3, 0)})
+
+	calls := 0
+	err := o.retry(func() error {
+		calls++
+		if calls < 3 {
+			return &fs.PathError{Op: "write", Path: "x", Err: syscall.EBUSY}
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success on third attempt, got err=%v calls=%d", err, calls)
+	}
+
+	calls = 0
+	permanent := &fs.PathError{Op: "write", Path: "x", Err: syscall.EACCES}
+	err = o.retry(func() error {
+		calls++
+		return permanent
+	})
+	if !errors.Is(err, syscall.EACCES) || calls != 1 {
+		t.Fatalf("expected permanent error without retry, got err=%v calls=%d", err, calls)
+	}
+
+	calls = 0
+	err = o.retry(func() error {
+		calls++
+		return syscall.EINTR
+	})
+	if !errors.Is(err, syscall.EINTR) || calls != 4 {
+		t.Fatalf("expected 1+3 attempts, got err=%v calls=%d", err, calls)
+	}
+}
+
+// flakyFS fails the first read of each file after a few bytes with EBUSY.
+type flakyFS struct {
+	fstest.MapFS
+	failed map[string]bool
+}
+
+func (f flakyFS) Open(name string) (fs.File, error) {
+	file, err := f.MapFS.Open(name)
+	if err != nil || f.failed[name] {
+		return file, err
+	}
+	if info, err := file.Stat(); err != nil || info.IsDir() {
+		return file, err
+	}
+	f.failed[name] = true
+	return &flakyFile{File: file}, nil
+}
+
+type flakyFile struct {
+	fs.File
+	read bool
+}
+
+func (f *flakyFile) Read(b []byte) (int, error) {
+	if f.read {
+		return 0, syscall.EBUSY
+	}
+	f.read = true
+	return f.File.Read(b[:min(len(b), 4)])
+}
+
+func TestRetryProgress(t *testing.T) {
+	src := flakyFS{MapFS: fstest.MapFS{"a.txt": {Data: []byte("0123456789")}}, failed: map[string]bool{}}
+	var last Progress
+	e, err := Extract(src, ".", "retry", t.TempDir(), WithRetry(2, 0), WithProgress(func(p Progress) {
+		if p.Bytes > p.TotalBytes {
+			t.Errorf("progress beyond total: %+v", p)
+		}
+		last = p
+	}))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+	if last.Bytes != 10 || last.Files != 1 {
+		t.Errorf("unexpected final progress %+v", last)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	o := newOptions([]Option{WithRetry(100, time.Second)})
+	if d := o.retryDelay(3); d != 8*time.Second {
+		t.Errorf("retryDelay(3) = %v", d)
+	}
+	if d := o.retryDelay(99); d != time.Second<<16 {
+		t.Errorf("expected the doubling capped, got %v", d)
+	}
+	o = newOptions([]Option{WithRetry(100, time.Duration(math.MaxInt64/1000))})
+	if d := o.retryDelay(40); d <= 0 {
+		t.Errorf("expected a saturated delay, got %v", d)
+	}
+}
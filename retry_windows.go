@@ -0,0 +1,29 @@
+package efs
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Windows error codes worth retrying: files held open by virus scanners and
+// indexers, and hiccups of SMB shares reached through UNC paths.
+var transientErrors = []syscall.Errno{
+	32,   // ERROR_SHARING_VIOLATION
+	33,   // ERROR_LOCK_VIOLATION
+	53,   // ERROR_BAD_NETPATH
+	54,   // ERROR_NETWORK_BUSY
+	59,   // ERROR_UNEXP_NET_ERR
+	64,   // ERROR_NETNAME_DELETED
+	121,  // ERROR_SEM_TIMEOUT
+	1231, // ERROR_NETWORK_UNREACHABLE
+}
+
+// isTransient reports whether err is a condition worth retrying.
+func isTransient(err error) bool {
+	for _, errno := range transientErrors {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
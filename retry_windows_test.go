@@ -0,0 +1,18 @@
+package efs
+
+import (
+	"fmt"
+	"syscall"
+	"testing"
+)
+
+func TestIsTransientNetwork(t *testing.T) {
+	for _, errno := range []syscall.Errno{32, 53, 64, 121} {
+		if !isTransient(fmt.Errorf("mkdir: %w", errno)) {
+			t.Errorf("errno %d should be transient", errno)
+		}
+	}
+	if isTransient(syscall.Errno(5)) { // ERROR_ACCESS_DENIED
+		t.Error("access denied should not be transient")
+	}
+}
@@ -0,0 +1,68 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// journal records the destination entries an extraction created, in creation
+// order, so that a failed ExtractTo can remove exactly those and leave
+// pre-existing content alone. A nil *journal records nothing. It is safe
+// for concurrent use.
+type journal struct {
+	mu      sync.Mutex
+	created []string
+}
+
+// create records that path is about to be created, unless it already exists.
+func (j *journal) create(path string) {
+	if j == nil {
+		return
+	}
+	if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+		j.mu.Lock()
+		j.created = append(j.created, path)
+		j.mu.Unlock()
+	}
+}
+
+// mkdirAll is os.MkdirAll that records every directory it is about to create.
+func (j *journal) mkdirAll(dir string, perm fs.FileMode) error {
+	if j != nil {
+		var missing []string
+		for p := dir; ; p = filepath.Dir(p) {
+			if _, err := os.Lstat(p); !errors.Is(err, fs.ErrNotExist) {
+				break
+			}
+			missing = append(missing, p)
+			if filepath.Dir(p) == p {
+				break
+			}
+		}
+		// Outermost first, so rollback removes children before parents.
+		j.mu.Lock()
+		for i := len(missing) - 1; i >= 0; i-- {
+			j.created = append(j.created, missing[i])
+		}
+		j.mu.Unlock()
+	}
+	return os.MkdirAll(dir, perm)
+}
+
+// rollback removes the recorded entries in reverse creation order. Directories
+// that have meanwhile gained foreign content are not empty and stay in place;
+// errors are ignored since rollback is best-effort cleanup after a failure.
+func (j *journal) rollback() {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for i := len(j.created) - 1; i >= 0; i-- {
+		_ = os.Remove(j.created[i])
+	}
+	j.created = nil
+}
@@ -0,0 +1,53 @@
+package efs
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// ExtractRootsToTemp extracts several subtrees of fsys (e.g. "templates" and
+// "static") into one new temporary directory. Unlike ExtractToTemp, each root
+// keeps its path inside the temp directory, so "web/static/app.css" ends up at
+// <dir>/web/static/app.css. Roots must be distinct, must not be "." and must
+// not contain one another. baseDir has the same meaning as the tempDir
+// parameter of ExtractToTemp.
+//
+// Example:
+//
+//	dir, cleanup, err := ExtractRootsToTemp(assets, []string{"templates", "static"}, "web", "")
+//	defer cleanup()
+func ExtractRootsToTemp(fsys fs.FS, roots []string, tempPrefix string, baseDir string, opts ...Option) (string, func(), error) {
+	sources, err := rootSources("extract roots", roots)
+	if err != nil {
+		return "", nil, err
+	}
+	e, err := extract(fsys, sources, tempPrefix, baseDir, newOptions(opts))
+	if err != nil {
+		return "", nil, err
+	}
+	return e.Dir(), func() { _ = e.Cleanup() }, nil
+}
+
+// rootSources validates roots and maps each onto its own path; op prefixes
+// error messages.
+func rootSources(op string, roots []string) ([]source, error) {
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("%s: no roots given", op)
+	}
+	sources := make([]source, 0, len(roots))
+	for _, r := range roots {
+		r = path.Clean(r)
+		if r == "." || !fs.ValidPath(r) {
+			return nil, fmt.Errorf("%s: invalid root %q", op, r)
+		}
+		for _, prev := range sources {
+			if r == prev.root || strings.HasPrefix(r, prev.root+"/") || strings.HasPrefix(prev.root, r+"/") {
+				return nil, fmt.Errorf("%s: %q overlaps %q", op, r, prev.root)
+			}
+		}
+		sources = append(sources, source{root: r, dest: r})
+	}
+	return sources, nil
+}
@@ -0,0 +1,44 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExtractRootsToTemp(t *testing.T) {
+	mem := fstest.MapFS{
+		"templates/index.html":  {Data: []byte("<html>")},
+		"web/static/app.css":    {Data: []byte("body{}")},
+		"internal/secret.txt":   {Data: []byte("no")},
+		"web/static/js/app.js":  {Data: []byte("js")},
+		"web/unrelated/skip.md": {Data: []byte("skip")},
+	}
+
+	dir, cleanup, err := ExtractRootsToTemp(mem, []string{"templates", "web/static"}, "roots", t.TempDir())
+	if err != nil {
+		t.Fatalf("ExtractRootsToTemp error: %v", err)
+	}
+	defer cleanup()
+
+	for _, want := range []string{"templates/index.html", "web/static/app.css", "web/static/js/app.js"} {
+		if _, err := os.Stat(filepath.Join(dir, want)); err != nil {
+			t.Errorf("expected %s: %v", want, err)
+		}
+	}
+	for _, unwanted := range []string{"internal", "web/unrelated"} {
+		if _, err := os.Stat(filepath.Join(dir, unwanted)); !os.IsNotExist(err) {
+			t.Errorf("did not expect %s, got err=%v", unwanted, err)
+		}
+	}
+}
+
+func TestExtractRootsRejectsOverlap(t *testing.T) {
+	mem := fstest.MapFS{"a/b/c.txt": {Data: []byte("C")}}
+	for _, roots := range [][]string{{"a", "a/b"}, {"a", "a"}, {"."}, {}} {
+		if _, _, err := ExtractRootsToTemp(mem, roots, "roots", t.TempDir()); err == nil {
+			t.Errorf("expected error for roots %q", roots)
+		}
+	}
+}
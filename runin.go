@@ -0,0 +1,55 @@
+package efs
+
+import (
+	"io/fs"
+	"os"
+)
+
+// WithChdir makes RunIn change the process working directory to the
+// extracted directory while fn runs and restore it afterwards. The working
+// directory is shared by all goroutines, so this suits command-line
+// programs, not servers doing other work concurrently.
+func WithChdir() Option {
+	return func(o *options) { o.chdir = true }
+}
+
+// RunIn extracts root from fsys into a new temporary directory, calls fn
+// with its absolute path and removes the directory afterwards, also when fn
+// panics (the panic then continues). With WithChdir, fn runs in the
+// extracted directory, which suits external tools that expect their assets
+// relative to the working directory. RunIn returns fn's error, else any
+// error from restoring the working directory or cleaning up.
+//
+// Example:
+//
+//	err := efs.RunIn(assets, "protoc", func(dir string) error {
+//		return exec.Command("./bin/protoc", "--version").Run()
+//	}, efs.WithChdir())
+func RunIn(fsys fs.FS, root string, fn func(dir string) error, opts ...Option) (err error) {
+	e, err := Extract(fsys, root, "run", "", opts...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cleanupErr := e.Cleanup(); err == nil {
+			err = cleanupErr
+		}
+	}()
+
+	if e.o.chdir {
+		prev, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		if err := os.Chdir(e.Dir()); err != nil {
+			return destErr(e.Dir(), err)
+		}
+		defer func() {
+			// Leave the directory before Cleanup removes it.
+			if chdirErr := os.Chdir(prev); err == nil {
+				err = chdirErr
+			}
+		}()
+	}
+	return fn(e.Dir())
+}
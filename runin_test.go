@@ -0,0 +1,66 @@
+package efs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRunIn(t *testing.T) {
+	SetDefaultBaseDir(t.TempDir())
+	defer SetDefaultBaseDir("")
+	mem := fstest.MapFS{"tool/assets/a.txt": {Data: []byte("A")}}
+	wd, _ := os.Getwd()
+
+	var seen string
+	err := RunIn(mem, "tool", func(dir string) error {
+		seen = dir
+		cwd, _ := os.Getwd()
+		if resolved, _ := filepath.EvalSymlinks(dir); cwd != resolved && cwd != dir {
+			t.Errorf("expected working directory %s, got %s", dir, cwd)
+		}
+		data, err := os.ReadFile("assets/a.txt")
+		if err != nil || string(data) != "A" {
+			t.Errorf("expected CWD-relative asset, got %q (%v)", data, err)
+		}
+		return nil
+	}, WithChdir())
+	if err != nil {
+		t.Fatalf("RunIn error: %v", err)
+	}
+	if cwd, _ := os.Getwd(); cwd != wd {
+		t.Errorf("expected working directory %s restored, got %s", wd, cwd)
+	}
+	if _, err := os.Stat(seen); !os.IsNotExist(err) {
+		t.Errorf("expected %s removed, got %v", seen, err)
+	}
+
+	failed := errors.New("tool failed")
+	if err := RunIn(mem, "tool", func(string) error { return failed }); !errors.Is(err, failed) {
+		t.Errorf("expected fn's error, got %v", err)
+	}
+}
+
+func TestRunInPanic(t *testing.T) {
+	SetDefaultBaseDir(t.TempDir())
+	defer SetDefaultBaseDir("")
+	mem := fstest.MapFS{"tool/a.txt": {Data: []byte("A")}}
+
+	var seen string
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to propagate")
+			}
+		}()
+		_ = RunIn(mem, "tool", func(dir string) error {
+			seen = dir
+			panic("boom")
+		})
+	}()
+	if _, err := os.Stat(seen); !os.IsNotExist(err) {
+		t.Errorf("expected %s removed after panic, got %v", seen, err)
+	}
+}
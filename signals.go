@@ -0,0 +1,11 @@
+//go:build !js
+
+package efs
+
+import (
+	"os"
+	"syscall"
+)
+
+// cleanupSignals are the signals StartCleanupListener reacts to.
+var cleanupSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP}
@@ -0,0 +1,10 @@
+package efs
+
+import (
+	"os"
+	"syscall"
+)
+
+// cleanupSignals are the signals StartCleanupListener reacts to. js/wasm has
+// no SIGHUP.
+var cleanupSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
@@ -0,0 +1,44 @@
+//go:build unix
+
+package efs
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCleanupListenerEscalates(t *testing.T) {
+	codes := make(chan int, 2)
+	removing := make(chan struct{})
+	block := make(chan struct{})
+	defer close(block)
+	exit, removeAll = func(code int) { codes <- code }, func(string) error {
+		close(removing)
+		<-block // A huge tree that takes forever
+		return nil
+	}
+	defer func() { exit, removeAll = os.Exit, os.RemoveAll }()
+
+	stop := StartCleanupListener(t.TempDir())
+	defer stop()
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	<-removing
+	select {
+	case code := <-codes:
+		t.Fatalf("exited with %d before cleanup finished", code)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+	select {
+	case code := <-codes:
+		if code != 128+int(syscall.SIGTERM) {
+			t.Errorf("expected exit code %d, got %d", 128+int(syscall.SIGTERM), code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal did not force an exit")
+	}
+}
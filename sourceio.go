@@ -0,0 +1,123 @@
+package efs
+
+import (
+	"hash"
+	"io"
+	"io/fs"
+	"sync"
+)
+
+// WithNoAtime opens source files with O_NOATIME, so reading a large tree
+// does not cause an inode update per file on the source disk. It applies when
+// the source is an efs.DirFS (use it instead of os.DirFS) and only on Linux;
+// files the process does not own are opened normally.
+func WithNoAtime() Option {
+	return func(o *options) { o.noAtime = true }
+}
+
+// WithSequentialRead advises the kernel (posix_fadvise) that source files are
+// read sequentially, enabling aggressive read-ahead on spinning disks and
+// network mounts. Like WithNoAtime it applies to efs.DirFS sources on Linux.
+func WithSequentialRead() Option {
+	return func(o *options) { o.sequentialRead = true }
+}
+
+// copyBufSize is the size of the buffers file contents are streamed
+// through, so memory use does not grow with the size of extracted files.
+const copyBufSize = 64 << 10
+
+var copyBufs = sync.Pool{New: func() any {
+	b := make([]byte, copyBufSize)
+	return &b
+}}
+
+// openSource opens the file name in fsys for reading, honoring the source
+// tuning options when fsys supports them and WithStrictSource.
+func (o *options) openSource(fsys fs.FS, name string) (io.ReadCloser, error) {
+	var f fs.File
+	var err error
+	if d, ok := fsys.(dirFS); ok && (o.noAtime || o.sequentialRead) {
+		f, err = d.openTuned(name, o.noAtime, o.sequentialRead)
+	} else {
+		f, err = fsys.Open(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if o.strictSource {
+		return openStrict(f)
+	}
+	return f, nil
+}
+
+// readSource reads the whole file name from fsys like openSource.
+func (o *options) readSource(fsys fs.FS, name string) ([]byte, error) {
+	r, err := o.openSource(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// copySource streams the file src of fsys into w through a pooled buffer. It
+// returns the number of bytes copied and, with WithAuditLog, their digest.
+// Errors opening or reading the source are returned as *SourceError.
+func (o *options) copySource(fsys fs.FS, src string, w io.Writer) (n int64, digest string, err error) {
+	f, err := o.openSource(fsys, src)
+	if err != nil {
+		return 0, "", sourceErr(src, err)
+	}
+	defer f.Close()
+	var h hash.Hash
+	if o.audit != nil {
+		h = o.digestHash().New()
+		w = io.MultiWriter(w, h)
+	}
+	r := &sourceReader{r: f}
+	buf := copyBufs.Get().(*[]byte)
+	defer copyBufs.Put(buf)
+	// Hide io.ReaderFrom and io.WriterTo so the copy goes through buf.
+	n, err = io.CopyBuffer(struct{ io.Writer }{w}, r, *buf)
+	if r.err != nil {
+		return n, "", sourceErr(src, r.err)
+	}
+	if err != nil {
+		return n, "", err
+	}
+	if h != nil {
+		digest = formatDigest(o.digestHash(), h.Sum(nil))
+	}
+	return n, digest, nil
+}
+
+// sourceReader records read errors, to tell them apart from write errors
+// after a copy.
+type sourceReader struct {
+	r   io.Reader
+	err error
+}
+
+func (s *sourceReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if err != nil && err != io.EOF {
+		s.err = err
+	}
+	return n, err
+}
+
+// openTuned opens name like Open, with the given tuning.
+func (d dirFS) openTuned(name string, noAtime, sequential bool) (fs.File, error) {
+	full, err := d.join("open", name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := openSource(full, noAtime)
+	if err != nil {
+		return nil, err
+	}
+	if sequential {
+		adviseSequential(f)
+	}
+	return f, nil
+}
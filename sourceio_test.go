@@ -0,0 +1,90 @@
+package efs
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSourceTuning(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "big.bin"), make([]byte, 1<<20), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := Extract(DirFS(src), ".", "tuned", t.TempDir(), WithNoAtime(), WithSequentialRead())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+	if err := e.Verify(); err != nil {
+		t.Errorf("Verify error: %v", err)
+	}
+	if r := e.Report(); r.Files != 1 || r.Bytes != 1<<20 {
+		t.Errorf("unexpected report %+v", r)
+	}
+}
+
+// readSizeFS records the largest buffer its files were read into.
+type readSizeFS struct {
+	fs.FS
+	largest *int
+}
+
+func (r readSizeFS) Open(name string) (fs.File, error) {
+	f, err := r.FS.Open(name)
+	if err != nil || path.Ext(name) == "" {
+		return f, err
+	}
+	return readSizeFile{f, r.largest}, nil
+}
+
+type readSizeFile struct {
+	fs.File
+	largest *int
+}
+
+func (f readSizeFile) Read(p []byte) (int, error) {
+	*f.largest = max(*f.largest, len(p))
+	return f.File.Read(p)
+}
+
+func TestStreamedCopy(t *testing.T) {
+	var largest int
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1<<16) // 1 MiB
+	mem := readSizeFS{fstest.MapFS{"assets/big.bin": {Data: data}}, &largest}
+
+	var audit bytes.Buffer
+	e, err := Extract(mem, "assets", "stream", t.TempDir(), WithAuditLog(&audit))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+	if got, err := os.ReadFile(e.Path("big.bin")); err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("extracted content differs (%v)", err)
+	}
+	if largest > copyBufSize {
+		t.Errorf("expected reads of at most %d bytes, got %d", copyBufSize, largest)
+	}
+	if !strings.Contains(audit.String(), dataDigest(SHA256, data)) {
+		t.Errorf("expected the streamed digest in the audit log, got %s", audit.String())
+	}
+
+	largest = 0
+	file, cleanup, err := ExtractFile(mem, "assets/big.bin", "stream", t.TempDir())
+	if err != nil {
+		t.Fatalf("ExtractFile error: %v", err)
+	}
+	defer cleanup()
+	if info, err := os.Stat(file); err != nil || info.Size() != int64(len(data)) || largest > copyBufSize {
+		t.Errorf("unexpected file %v (%v), largest read %d", info, err, largest)
+	}
+}
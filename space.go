@@ -0,0 +1,46 @@
+package efs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInsufficientSpace is matched (via errors.Is) by *InsufficientSpaceError.
+var ErrInsufficientSpace = errors.New("insufficient space")
+
+// InsufficientSpaceError is reported (wrapped in a *DestError) when
+// WithSpaceCheck finds that the destination cannot hold the extraction.
+type InsufficientSpaceError struct {
+	Required  int64 // Bytes the extraction would write
+	Available int64 // Bytes available on the destination file system
+}
+
+func (e *InsufficientSpaceError) Error() string {
+	return fmt.Sprintf("insufficient space: %d bytes required, %d available", e.Required, e.Available)
+}
+
+func (e *InsufficientSpaceError) Is(target error) bool { return target == ErrInsufficientSpace }
+
+// WithSpaceCheck compares the total size of the files to extract against the
+// space available on the destination file system before anything is written,
+// and fails fast with an *InsufficientSpaceError instead of running into a
+// full disk halfway through. The estimate ignores file system overhead and
+// files that ExtractTo would overwrite. Where free space cannot be
+// determined, such as on js/wasm, the check passes.
+func WithSpaceCheck() Option {
+	return func(o *options) { o.spaceCheck = true }
+}
+
+// checkSpace fails if WithSpaceCheck was given and the file system holding
+// dir, or its nearest existing ancestor, has less than required bytes free.
+func (o *options) checkSpace(dir string, required int64) error {
+	if !o.spaceCheck {
+		return nil
+	}
+	dir = existingDir(dir)
+	avail := freeSpace(dir)
+	if avail < 0 || required <= avail {
+		return nil
+	}
+	return &DestError{Path: dir, Err: &InsufficientSpaceError{Required: required, Available: avail}}
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package efs
+
+import (
+	"errors"
+	"syscall"
+)
+
+// freeSpace reports -1: free space cannot be determined on this platform.
+func freeSpace(path string) int64 {
+	return -1
+}
+
+// isDiskFull reports whether err was caused by a full file system.
+func isDiskFull(err error) bool {
+	return errors.Is(err, syscall.ENOSPC)
+}
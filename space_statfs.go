@@ -0,0 +1,23 @@
+//go:build linux || darwin || freebsd
+
+package efs
+
+import (
+	"errors"
+	"syscall"
+)
+
+// freeSpace returns the bytes available to unprivileged users on the file
+// system holding path, or -1 if it cannot be determined.
+func freeSpace(path string) int64 {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return -1
+	}
+	return int64(st.Bavail) * int64(st.Bsize)
+}
+
+// isDiskFull reports whether err was caused by a full file system.
+func isDiskFull(err error) bool {
+	return errors.Is(err, syscall.ENOSPC)
+}
@@ -0,0 +1,37 @@
+package efs
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSpaceCheck(t *testing.T) {
+	dir := t.TempDir()
+	if freeSpace(dir) < 0 {
+		t.Skip("free space unknown on this platform")
+	}
+
+	o := newOptions([]Option{WithSpaceCheck()})
+	err := o.checkSpace(filepath.Join(dir, "not", "yet"), 1<<62)
+	var ise *InsufficientSpaceError
+	if !errors.Is(err, ErrInsufficientSpace) || !errors.As(err, &ise) || ise.Required != 1<<62 || ise.Available <= 0 {
+		t.Fatalf("expected InsufficientSpaceError, got %v", err)
+	}
+	var de *DestError
+	if !errors.As(err, &de) || de.Path != dir {
+		t.Errorf("expected DestError for nearest existing dir %s, got %v", dir, err)
+	}
+
+	if err := newOptions(nil).checkSpace(dir, 1<<62); err != nil {
+		t.Errorf("expected no check without WithSpaceCheck, got %v", err)
+	}
+
+	mem := fstest.MapFS{"root/a.txt": {Data: []byte("A")}}
+	ex, err := Extract(mem, "root", "space", dir, WithSpaceCheck())
+	if err != nil {
+		t.Fatalf("expected small extraction to pass the check, got %v", err)
+	}
+	ex.Cleanup()
+}
@@ -0,0 +1,34 @@
+package efs
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	errorHandleDiskFull syscall.Errno = 39  // ERROR_HANDLE_DISK_FULL
+	errorDiskFull       syscall.Errno = 112 // ERROR_DISK_FULL
+)
+
+var procGetDiskFreeSpaceExW = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// freeSpace returns the bytes available to the calling user on the volume
+// holding path, or -1 if it cannot be determined.
+func freeSpace(path string) int64 {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return -1
+	}
+	var avail uint64
+	r, _, _ := procGetDiskFreeSpaceExW.Call(uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&avail)), 0, 0)
+	if r == 0 {
+		return -1
+	}
+	return int64(avail)
+}
+
+// isDiskFull reports whether err was caused by a full volume.
+func isDiskFull(err error) bool {
+	return errors.Is(err, errorDiskFull) || errors.Is(err, errorHandleDiskFull)
+}
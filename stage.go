@@ -0,0 +1,83 @@
+package efs
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WithStageNear creates the temporary directory (or file) on the same file
+// system as path, the intended final destination, instead of in the usual
+// base directory. A finished extraction can then be moved into place with a
+// single atomic rename (see Extraction.MoveTo) rather than failing with
+// EXDEV after the whole tree has been written to /tmp. The staging entry is
+// created in the nearest existing ancestor of path's parent directory. An
+// explicit tempDir argument or WithTempDir still wins; WithStageNear takes
+// precedence over WithPreferTmpfs and DefaultBaseDir.
+func WithStageNear(path string) Option {
+	return func(o *options) { o.stageNear = path }
+}
+
+// stageBase returns the nearest existing directory that would contain path.
+func stageBase(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return existingDir(filepath.Dir(path))
+}
+
+// existingDir returns dir if it exists, else its nearest existing ancestor.
+func existingDir(dir string) string {
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// MoveTo moves the extracted directory to dst and hands it over to the
+// caller: afterwards Cleanup no longer removes anything, and Dir and Path
+// refer to a location that no longer exists. An existing tree at dst is
+// replaced as a whole, as with WithAtomic; missing parents of dst are
+// created. Locks, bind mounts and immutability set up for the extraction are
+// released before the move, which first waits for a WithPriority
+// extraction to complete. If the move fails, dst is left untouched and the
+// tree stays where it was, to be removed by Cleanup.
+//
+// The move is a single atomic rename when dst is on the same file system as
+// the extraction; use WithStageNear to arrange that. Otherwise the tree is
+// first copied and synced next to dst and then renamed into place, with the
+// same all-or-nothing outcome but without ownership and extended attributes.
+func (e *Extraction) MoveTo(dst string) error {
+	absDst, err := filepath.Abs(dst)
+	if err != nil {
+		absDst = dst
+	}
+
+	if err := e.Wait(); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.done {
+		return &DestError{Path: e.dir, Err: fmt.Errorf("move: %w", fs.ErrNotExist)}
+	}
+	if err := e.releaseLocked(); err != nil {
+		return destErr(e.dir, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(absDst), e.o.dirPerm()); err != nil {
+		return destErr(absDst, err)
+	}
+	if err := moveTree(e.dir, absDst, e.o.dirPerm()); err != nil {
+		return destErr(absDst, err)
+	}
+	e.done = true
+	trackDone(e.dir)
+	return nil
+}
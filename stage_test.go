@@ -0,0 +1,48 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestStageNearMoveTo(t *testing.T) {
+	mem := fstest.MapFS{"root/a.txt": {Data: []byte("new")}}
+	base := t.TempDir()
+	final := filepath.Join(base, "srv", "app", "assets")
+
+	ex, err := Extract(mem, "root", "stage", "", WithStageNear(final))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+	if got := filepath.Dir(ex.Dir()); got != base {
+		t.Fatalf("expected staging in nearest existing ancestor %s, got %s", base, got)
+	}
+
+	// An existing tree at the destination is replaced as a whole.
+	if err := os.MkdirAll(final, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(final, "stale.txt"), []byte("old"), 0o644)
+
+	if err := ex.MoveTo(final); err != nil {
+		t.Fatalf("MoveTo error: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(final, "a.txt")); err != nil || string(data) != "new" {
+		t.Fatalf("expected moved a.txt, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(final, "stale.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected previous tree to be replaced, got %v", err)
+	}
+	if err := ex.Cleanup(); err != nil {
+		t.Fatalf("Cleanup after MoveTo error: %v", err)
+	}
+	if _, err := os.Stat(final); err != nil {
+		t.Fatalf("expected Cleanup to leave the moved tree alone, got %v", err)
+	}
+	if err := ex.MoveTo(final + "2"); err == nil {
+		t.Error("expected second MoveTo to fail")
+	}
+}
@@ -0,0 +1,150 @@
+package efs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CleanStale removes the temporary directories that extractions with
+// tempPrefix prefix left in baseDir, typically because the program crashed
+// before Cleanup ran, and returns the paths it removed. An empty baseDir
+// means the default base directory (see SetDefaultBaseDir). The per-user
+// directories of WithUserIsolation and the namespace directories of
+// WithNamespace below baseDir are searched as well.
+//
+// Only names efs generates for prefix are considered: "<prefix>-<random>",
+// "<prefix>-<id>-<random>" (WithIDInName), "<prefix>-<key>" and the hidden
+// staging directories of WithContentName. Names from WithNameGenerator are
+// not recognized. A matching directory is removed when it was last modified
+// more than olderThan ago; for a WithContentName directory, the age is that
+// of its CompleteFileName sentinel, which each reuse refreshes. Directories
+// still held by WithLock and those whose WithMetaFile marker names a running
+// process are left alone whatever their age, so an extraction that outlives
+// olderThan should use one of these options. Symlinks and files are never
+// touched.
+//
+// CleanStale keeps going when a directory cannot be removed and returns the
+// failures joined as *DestError values.
+//
+// Example:
+//
+//	removed, err := efs.CleanStale("", "myapp", 24*time.Hour)
+//	if err != nil { log.Print(err) }
+//	log.Printf("removed %d stale extraction(s)", len(removed))
+func CleanStale(baseDir, prefix string, olderThan time.Duration) ([]string, error) {
+	if err := validatePrefix(prefix); err != nil {
+		return nil, err
+	}
+	if baseDir == "" {
+		baseDir = DefaultBaseDir()
+	}
+	if _, err := os.ReadDir(baseDir); err != nil {
+		return nil, destErr(baseDir, err)
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	var removed []string
+	var errs []error
+	for _, parent := range staleParents(baseDir) {
+		entries, err := os.ReadDir(parent)
+		if err != nil {
+			continue // A subdirectory of another user, or gone
+		}
+		for _, d := range entries {
+			dir := filepath.Join(parent, d.Name())
+			if !d.IsDir() || !generatedName(dir, d.Name(), prefix) || !abandoned(dir, cutoff) {
+				continue
+			}
+			if err := os.RemoveAll(dir); err != nil {
+				errs = append(errs, destErr(dir, err))
+				continue
+			}
+			removed = append(removed, dir)
+		}
+	}
+	return removed, errors.Join(errs...)
+}
+
+// staleParents returns baseDir and the "efs-*" per-user and namespace
+// directories up to two levels below it.
+func staleParents(baseDir string) []string {
+	parents, level := []string{baseDir}, []string{baseDir}
+	for range 2 {
+		var next []string
+		for _, dir := range level {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				continue
+			}
+			for _, d := range entries {
+				if d.IsDir() && strings.HasPrefix(d.Name(), "efs-") {
+					next = append(next, filepath.Join(dir, d.Name()))
+				}
+			}
+		}
+		parents, level = append(parents, next...), next
+	}
+	return parents
+}
+
+// generatedName reports whether name, the base name of dir, has the shape
+// of a temp directory efs created for prefix.
+func generatedName(dir, name, prefix string) bool {
+	rest, ok := strings.CutPrefix(name, prefix+"-")
+	if !ok {
+		// Staging directories of WithContentName
+		rest, ok = strings.CutPrefix(name, "."+prefix+"-")
+		return ok && isDigits(rest)
+	}
+	if isDigits(rest) || isHexKey(rest) {
+		return true
+	}
+	id, suffix, ok := cutLast(rest, "-")
+	if !ok || !isDigits(suffix) {
+		return false
+	}
+	if isHexKey(id) {
+		return true // Generated extraction ID
+	}
+	m, err := ReadMeta(dir)
+	return err == nil && m.ID == id
+}
+
+// abandoned reports whether the extraction directory dir has not been
+// modified since cutoff and is not known to be in use.
+func abandoned(dir string, cutoff time.Time) bool {
+	if IsLocked(dir) {
+		return false
+	}
+	if m, err := ReadMeta(dir); err == nil && processAlive(m.PID) {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(dir, CompleteFileName))
+	if err != nil {
+		if info, err = os.Stat(dir); err != nil {
+			return false
+		}
+	}
+	return info.ModTime().Before(cutoff)
+}
+
+// cutLast slices s around the last sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// isHexKey reports whether s looks like a generated extraction ID or a
+// WithContentName key: 16 lowercase hex characters.
+func isHexKey(s string) bool {
+	return len(s) == 16 && strings.Trim(s, "0123456789abcdef") == ""
+}
@@ -0,0 +1,90 @@
+package efs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestCleanStale(t *testing.T) {
+	base := t.TempDir()
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+	old := time.Now().Add(-2 * time.Hour)
+	extract := func(opts ...Option) string {
+		t.Helper()
+		dir, _, err := ExtractToTemp(mem, ".", "app", base, opts...)
+		if err != nil {
+			t.Fatalf("ExtractToTemp error: %v", err)
+		}
+		return dir
+	}
+	// aged backdates dir as if it had been left behind two hours ago.
+	aged := func(dir string) string {
+		t.Helper()
+		if err := os.Chtimes(dir, old, old); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	// withMeta rewrites the WithMetaFile marker of dir for pid.
+	withMeta := func(dir string, pid int) string {
+		t.Helper()
+		m, err := ReadMeta(dir)
+		if err != nil {
+			t.Fatalf("ReadMeta error: %v", err)
+		}
+		m.PID, m.Created = pid, old
+		data, _ := json.Marshal(m)
+		os.WriteFile(filepath.Join(dir, MetaFileName), data, 0o600)
+		return dir
+	}
+
+	plain := aged(extract())
+	namespaced := aged(extract(WithNamespace("tools")))
+	crashed := extract()
+	os.WriteFile(filepath.Join(crashed, LockFileName), nil, 0o600) // Holder is gone
+	aged(crashed)
+	metaDead := aged(withMeta(extract(WithMetaFile()), 0))
+	shared, err := Extract(mem, ".", "app", base, WithContentName())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	os.Chtimes(shared.Path(CompleteFileName), old, old)
+
+	locked, err := Extract(mem, ".", "app", base, WithLock())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer locked.Cleanup()
+	aged(locked.Dir())
+	metaLive := aged(withMeta(extract(WithMetaFile()), os.Getpid()))
+	fresh := extract()
+	unrelated := filepath.Join(base, "app-data-123")
+	os.Mkdir(unrelated, 0o700)
+	aged(unrelated)
+	other, _, err := ExtractToTemp(mem, ".", "other", base)
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	aged(other)
+
+	removed, err := CleanStale(base, "app", time.Hour)
+	if err != nil {
+		t.Fatalf("CleanStale error: %v", err)
+	}
+	want := []string{plain, namespaced, crashed, metaDead, shared.Dir()}
+	slices.Sort(removed)
+	slices.Sort(want)
+	if !slices.Equal(removed, want) {
+		t.Errorf("expected removed %v, got %v", want, removed)
+	}
+	for _, dir := range []string{locked.Dir(), metaLive, fresh, unrelated, other} {
+		if _, err := os.Stat(dir); err != nil {
+			t.Errorf("expected %s to be kept: %v", dir, err)
+		}
+	}
+}
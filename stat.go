@@ -0,0 +1,40 @@
+package efs
+
+import "io/fs"
+
+// TreeStats summarizes what extracting a tree would write.
+type TreeStats struct {
+	Files       int
+	Dirs        int
+	Bytes       int64  // Total size of all files
+	Largest     string // Slash-separated path of the largest file, relative to root; "" if there are no files
+	LargestSize int64
+}
+
+// Stat walks root in fsys and returns the number of files and directories an
+// extraction would create, their total size and the largest file, without
+// writing anything. Use it to decide whether extraction is feasible on
+// constrained devices. If root is empty, "." is used; root handling otherwise
+// matches ExtractToTemp.
+func Stat(fsys fs.FS, root string) (TreeStats, error) {
+	if root == "" {
+		root = "."
+	}
+	var st TreeStats
+	entries, err := prepare(fsys, []source{{root: root, dest: "."}}, newOptions(nil))
+	if err != nil {
+		return st, err
+	}
+	for _, e := range entries {
+		if e.d.IsDir() {
+			st.Dirs++
+			continue
+		}
+		st.Files++
+		st.Bytes += e.size
+		if st.Largest == "" || e.size > st.LargestSize {
+			st.Largest, st.LargestSize = e.rel, e.size
+		}
+	}
+	return st, nil
+}
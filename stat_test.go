@@ -0,0 +1,28 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestStat(t *testing.T) {
+	mem := fstest.MapFS{
+		"root/a.txt":       {Data: []byte("A")},
+		"root/sub/big.bin": {Data: []byte("0123456789")},
+		"root/sub/c.txt":   {Data: []byte("CCC")},
+	}
+	st, err := Stat(mem, "root")
+	if err != nil {
+		t.Fatalf("Stat error: %v", err)
+	}
+	want := TreeStats{Files: 3, Dirs: 1, Bytes: 14, Largest: "sub/big.bin", LargestSize: 10}
+	if st != want {
+		t.Errorf("expected %+v, got %+v", want, st)
+	}
+
+	if _, err := Stat(mem, "missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package efs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// ErrInconsistentSource is reported under WithStrictSource when the source
+// file system contradicts itself or returns names no real file system would.
+var ErrInconsistentSource = errors.New("inconsistent source file system")
+
+// Limits on names accepted by WithStrictSource, matching common file systems.
+const (
+	maxNameLen = 255  // Bytes per path element
+	maxPathLen = 4096 // Bytes per slash-separated path
+)
+
+// WithStrictSource validates what the source fs.FS reports instead of
+// trusting it, for file systems backed by archives, network services or
+// other code that may misbehave. Extraction fails with a *SourceError
+// wrapping ErrInconsistentSource, naming the entry and the discrepancy, when
+//   - a directory entry's name is empty, ".", "..", contains a slash or NUL,
+//     or is longer than 255 bytes, or its path is longer than 4096 bytes;
+//   - the same path is reported twice by a directory listing;
+//   - a DirEntry's name or type disagrees with what Stat reports for it;
+//   - reading a file returns more or fewer bytes than Stat reported.
+//
+// Reads are cut off one byte past the reported size, so a reader that never
+// ends cannot fill the disk. The checks cost an extra Stat per entry.
+func WithStrictSource() Option {
+	return func(o *options) { o.strictSource = true }
+}
+
+// inconsistent returns an ErrInconsistentSource error for the source path p.
+func inconsistent(p, format string, args ...any) error {
+	return sourceErr(p, fmt.Errorf("%w: %s", ErrInconsistentSource, fmt.Sprintf(format, args...)))
+}
+
+// checkEntry validates the walked entry d at p against fsys. seen collects
+// the paths reported so far by the walk.
+func checkEntry(fsys fs.FS, p string, d fs.DirEntry, seen map[string]bool) error {
+	name := d.Name()
+	switch {
+	case name == "" || name == "." && p != "." || name == "..":
+		return inconsistent(p, "invalid name %q", name)
+	case strings.ContainsAny(name, "/\x00"):
+		return inconsistent(p, "name %q contains a slash or NUL", name)
+	case len(name) > maxNameLen:
+		return inconsistent(p, "name is %d bytes long, more than %d", len(name), maxNameLen)
+	case len(p) > maxPathLen:
+		return inconsistent(p, "path is %d bytes long, more than %d", len(p), maxPathLen)
+	case p != "." && path.Base(p) != name:
+		return inconsistent(p, "entry name %q does not match its path", name)
+	case seen[p]:
+		return inconsistent(p, "listed more than once")
+	}
+	seen[p] = true
+
+	info, err := fs.Stat(fsys, p)
+	if err != nil {
+		return sourceErr(p, err)
+	}
+	// fs.Stat follows symlinks, so only compare types for other entries.
+	if d.Type()&fs.ModeSymlink == 0 && info.Mode().Type() != d.Type() {
+		return inconsistent(p, "directory entry reports type %v, Stat reports %v", d.Type(), info.Mode().Type())
+	}
+	if info.Name() != name && p != "." {
+		return inconsistent(p, "directory entry reports name %q, Stat reports %q", name, info.Name())
+	}
+	return nil
+}
+
+// openStrict wraps the regular file f so that reading it fails unless it
+// holds exactly as many bytes as Stat reports.
+func openStrict(f fs.File) (io.ReadCloser, error) {
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	size := info.Size()
+	if size < 0 {
+		f.Close()
+		return nil, fmt.Errorf("%w: Stat reports size %d", ErrInconsistentSource, size)
+	}
+	return &strictReader{f: f, r: io.LimitReader(f, size+1), size: size}, nil
+}
+
+// strictReader reads a file cut off one byte past its reported size and
+// checks the size at EOF.
+type strictReader struct {
+	f       fs.File
+	r       io.Reader
+	size, n int64
+}
+
+func (s *strictReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.n += int64(n)
+	switch {
+	case s.n > s.size:
+		return n, fmt.Errorf("%w: read more than the %d bytes Stat reports", ErrInconsistentSource, s.size)
+	case err == io.EOF && s.n != s.size:
+		return n, fmt.Errorf("%w: read %d bytes, Stat reports %d", ErrInconsistentSource, s.n, s.size)
+	}
+	return n, err
+}
+
+func (s *strictReader) Close() error {
+	return s.f.Close()
+}
@@ -0,0 +1,114 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// lyingFS is a MapFS whose directory listings and file sizes can be falsified.
+type lyingFS struct {
+	fstest.MapFS
+	readDir func(entries []fs.DirEntry) []fs.DirEntry // Rewrites every listing if set
+	size    int64                                     // Size reported by open files if non-zero
+}
+
+func (l lyingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := l.MapFS.ReadDir(name)
+	if err == nil && l.readDir != nil {
+		entries = l.readDir(entries)
+	}
+	return entries, err
+}
+
+func (l lyingFS) Open(name string) (fs.File, error) {
+	f, err := l.MapFS.Open(name)
+	if err != nil || l.size == 0 {
+		return f, err
+	}
+	return sizedFile{f, l.size}, nil
+}
+
+type sizedFile struct {
+	fs.File
+	size int64
+}
+
+func (f sizedFile) Stat() (fs.FileInfo, error) {
+	info, err := f.File.Stat()
+	if err != nil || info.IsDir() {
+		return info, err
+	}
+	return sizedInfo{info, f.size}, nil
+}
+
+type sizedInfo struct {
+	fs.FileInfo
+	size int64
+}
+
+func (i sizedInfo) Size() int64 { return i.size }
+
+// fakeEntry is a DirEntry with an arbitrary name and type.
+type fakeEntry struct {
+	name string
+	mode fs.FileMode
+}
+
+func (e fakeEntry) Name() string               { return e.name }
+func (e fakeEntry) IsDir() bool                { return e.mode.IsDir() }
+func (e fakeEntry) Type() fs.FileMode          { return e.mode.Type() }
+func (e fakeEntry) Info() (fs.FileInfo, error) { return e, nil }
+func (e fakeEntry) Size() int64                { return 0 }
+func (e fakeEntry) Mode() fs.FileMode          { return e.mode }
+func (e fakeEntry) ModTime() time.Time         { return time.Time{} }
+func (e fakeEntry) Sys() any                   { return nil }
+
+func TestWithStrictSource(t *testing.T) {
+	files := fstest.MapFS{
+		"assets/a.txt": {Data: []byte("AAAA")},
+		"assets/b.txt": {Data: []byte("B")},
+	}
+	tests := []struct {
+		name string
+		fsys fs.FS
+		want string
+	}{
+		{"duplicate", lyingFS{MapFS: files, readDir: func(entries []fs.DirEntry) []fs.DirEntry {
+			return append(entries, entries[0])
+		}}, "listed more than once"},
+		{"escaping name", lyingFS{MapFS: files, readDir: func(entries []fs.DirEntry) []fs.DirEntry {
+			return append(entries, fakeEntry{name: "../evil"})
+		}}, "contains a slash"},
+		{"type mismatch", lyingFS{MapFS: files, readDir: func(entries []fs.DirEntry) []fs.DirEntry {
+			if len(entries) > 0 && entries[0].Name() == "a.txt" {
+				entries[0] = fakeEntry{name: "a.txt", mode: fs.ModeDir}
+			}
+			return entries
+		}}, "Stat reports"},
+		{"long name", fstest.MapFS{"assets/" + strings.Repeat("n", 300): {Data: []byte("x")}}, "bytes long"},
+		{"oversized read", lyingFS{MapFS: files, size: 2}, "read more than the 2 bytes"},
+		{"short read", lyingFS{MapFS: files, size: 10}, "read 4 bytes, Stat reports 10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Extract(tt.fsys, "assets", "strict", t.TempDir(), WithStrictSource())
+			var se *SourceError
+			if !errors.Is(err, ErrInconsistentSource) || !errors.As(err, &se) {
+				t.Fatalf("expected a *SourceError wrapping ErrInconsistentSource, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected %q in %v", tt.want, err)
+			}
+		})
+	}
+
+	ex, err := Extract(files, "assets", "strict", t.TempDir(), WithStrictSource())
+	if err != nil {
+		t.Fatalf("expected a well-behaved source to pass, got %v", err)
+	}
+	ex.Cleanup()
+}
@@ -0,0 +1,42 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// ExtractSubset extracts exactly the paths listed in manifest from fsys into a
+// new temporary directory, for deployments that materialize only the assets a
+// given configuration needs. Each path keeps its location inside the temp
+// directory, so "img/logo.png" ends up at <dir>/img/logo.png; a listed
+// directory brings its whole subtree. Every path is checked before anything
+// is written, and all missing paths are reported together as *SourceError
+// values joined with errors.Join. Paths must be distinct and must not contain
+// one another. baseDir has the same meaning as the tempDir parameter of
+// ExtractToTemp.
+//
+// Example:
+//
+//	dir, cleanup, err := ExtractSubset(assets, []string{"img/logo.png", "locales/sv"}, "app", "")
+//	defer cleanup()
+func ExtractSubset(fsys fs.FS, manifest []string, tempPrefix string, baseDir string, opts ...Option) (string, func(), error) {
+	sources, err := rootSources("extract subset", manifest)
+	if err != nil {
+		return "", nil, err
+	}
+	o := newOptions(opts)
+	var errs []error
+	for _, src := range sources {
+		if _, err := fs.Stat(fsys, src.root); err != nil {
+			errs = append(errs, o.redactErr(sourceErr(src.root, err), src.root))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return "", nil, err
+	}
+	e, err := extract(fsys, sources, tempPrefix, baseDir, o)
+	if err != nil {
+		return "", nil, err
+	}
+	return e.Dir(), func() { _ = e.Cleanup() }, nil
+}
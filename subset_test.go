@@ -0,0 +1,46 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExtractSubset(t *testing.T) {
+	mem := fstest.MapFS{
+		"img/logo.png":     {Data: []byte("L")},
+		"img/banner.png":   {Data: []byte("B")},
+		"locales/sv/a.txt": {Data: []byte("SV")},
+		"locales/en/a.txt": {Data: []byte("EN")},
+	}
+
+	dir, cleanup, err := ExtractSubset(mem, []string{"img/logo.png", "locales/sv"}, "subset", t.TempDir())
+	if err != nil {
+		t.Fatalf("ExtractSubset error: %v", err)
+	}
+	defer cleanup()
+
+	for _, want := range []string{"img/logo.png", "locales/sv/a.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(want))); err != nil {
+			t.Errorf("expected %s: %v", want, err)
+		}
+	}
+	for _, unwanted := range []string{"img/banner.png", "locales/en"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(unwanted))); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected %s not extracted, got %v", unwanted, err)
+		}
+	}
+
+	base := t.TempDir()
+	_, _, err = ExtractSubset(mem, []string{"img/logo.png", "nope.txt", "gone/x"}, "subset", base)
+	if !errors.Is(err, fs.ErrNotExist) || !strings.Contains(err.Error(), "nope.txt") || !strings.Contains(err.Error(), "gone/x") {
+		t.Fatalf("expected all missing paths reported, got %v", err)
+	}
+	if entries, _ := os.ReadDir(base); len(entries) != 0 {
+		t.Errorf("expected nothing written, got %v", entries)
+	}
+}
@@ -0,0 +1,143 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// SupportFile is a shell completion or man page handled by
+// InstallSupportFiles.
+type SupportFile struct {
+	Kind   string // "bash", "zsh", "fish" or "man"
+	Source string // Slash-separated path within the source fs.FS
+	Path   string // Destination on disk
+}
+
+// InstallSupportFiles installs the shell completions and man pages of a CLI
+// found below root in fsys into the conventional per-user locations, so the
+// tool does not need a package manager to ship them. Files are picked up by
+// layout:
+//
+//	completions/bash/<file>  ->  $XDG_DATA_HOME/bash-completion/completions/<file>
+//	completions/zsh/<file>   ->  $XDG_DATA_HOME/zsh/site-functions/<file>
+//	completions/fish/<file>  ->  $XDG_CONFIG_HOME/fish/completions/<file>
+//	man/**/<name>.<N>[.gz]   ->  $XDG_DATA_HOME/man/man<N>/<name>.<N>[.gz]
+//
+// XDG_DATA_HOME defaults to ~/.local/share and XDG_CONFIG_HOME to ~/.config.
+// Other files are ignored. Existing files are replaced. With dryRun nothing
+// is written; the result lists what would be installed. zsh only finds the
+// completions if the directory is in its fpath. On Windows, where none of
+// these conventions apply, nothing is installed.
+func InstallSupportFiles(fsys fs.FS, root string, dryRun bool) ([]SupportFile, error) {
+	files, err := supportFiles(fsys, root)
+	if err != nil || dryRun {
+		return files, err
+	}
+	for _, f := range files {
+		data, err := fs.ReadFile(fsys, f.Source)
+		if err != nil {
+			return nil, sourceErr(f.Source, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+			return nil, destErr(f.Path, err)
+		}
+		if err := os.WriteFile(f.Path, data, 0o644); err != nil {
+			return nil, destErr(f.Path, err)
+		}
+	}
+	return files, nil
+}
+
+// UninstallSupportFiles removes the files InstallSupportFiles would install
+// for the same fsys and root and returns those that existed. Directories are
+// left in place. With dryRun nothing is removed.
+func UninstallSupportFiles(fsys fs.FS, root string, dryRun bool) ([]SupportFile, error) {
+	files, err := supportFiles(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+	var removed []SupportFile
+	for _, f := range files {
+		if _, err := os.Lstat(f.Path); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if !dryRun {
+			if err := os.Remove(f.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return removed, destErr(f.Path, err)
+			}
+		}
+		removed = append(removed, f)
+	}
+	return removed, nil
+}
+
+// supportFiles maps the completions and man pages below root to their
+// destinations.
+func supportFiles(fsys fs.FS, root string) ([]SupportFile, error) {
+	if root == "" {
+		root = "."
+	}
+	if _, err := fs.Stat(fsys, root); err != nil {
+		return nil, sourceErr(root, err)
+	}
+	if runtime.GOOS == "windows" {
+		return nil, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	data := xdgDir("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	config := xdgDir("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	shellDirs := map[string]string{
+		"bash": filepath.Join(data, "bash-completion", "completions"),
+		"zsh":  filepath.Join(data, "zsh", "site-functions"),
+		"fish": filepath.Join(config, "fish", "completions"),
+	}
+
+	var files []SupportFile
+	err = fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		parts := strings.Split(relPath(root, p), "/")
+		switch {
+		case len(parts) == 3 && parts[0] == "completions" && shellDirs[parts[1]] != "":
+			files = append(files, SupportFile{Kind: parts[1], Source: p, Path: filepath.Join(shellDirs[parts[1]], parts[2])})
+		case len(parts) >= 2 && parts[0] == "man":
+			if section := manSection(d.Name()); section != "" {
+				files = append(files, SupportFile{Kind: "man", Source: p, Path: filepath.Join(data, "man", "man"+section, d.Name())})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, sourceErr(root, err)
+	}
+	return files, nil
+}
+
+// manSection returns the section digit of a man page name such as "tool.1"
+// or "tool.1.gz", or "" if name is not one.
+func manSection(name string) string {
+	name = strings.TrimSuffix(name, ".gz")
+	ext := path.Ext(name)
+	if len(ext) == 2 && ext[1] >= '1' && ext[1] <= '9' {
+		return ext[1:]
+	}
+	return ""
+}
+
+// xdgDir returns the absolute directory in the environment variable key, or
+// def if it is unset or relative, as the XDG specification requires.
+func xdgDir(key, def string) string {
+	if dir := os.Getenv(key); filepath.IsAbs(dir) {
+		return dir
+	}
+	return def
+}
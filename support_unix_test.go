@@ -0,0 +1,60 @@
+//go:build unix
+
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestInstallSupportFiles(t *testing.T) {
+	data, config := t.TempDir(), t.TempDir()
+	t.Setenv("XDG_DATA_HOME", data)
+	t.Setenv("XDG_CONFIG_HOME", config)
+	mem := fstest.MapFS{
+		"share/completions/bash/tool":      {Data: []byte("complete -F _tool tool")},
+		"share/completions/fish/tool.fish": {Data: []byte("complete -c tool")},
+		"share/completions/tcsh/tool":      {Data: []byte("ignored")},
+		"share/man/tool.1":                 {Data: []byte(".TH TOOL 1")},
+		"share/man/man5/toolrc.5.gz":       {Data: []byte("gz")},
+		"share/man/README":                 {Data: []byte("ignored")},
+	}
+	want := map[string]string{
+		filepath.Join(data, "bash-completion", "completions", "tool"): "complete -F _tool tool",
+		filepath.Join(config, "fish", "completions", "tool.fish"):     "complete -c tool",
+		filepath.Join(data, "man", "man1", "tool.1"):                  ".TH TOOL 1",
+		filepath.Join(data, "man", "man5", "toolrc.5.gz"):             "gz",
+	}
+
+	files, err := InstallSupportFiles(mem, "share", true)
+	if err != nil || len(files) != len(want) {
+		t.Fatalf("expected %d planned files, got %v, %v", len(want), files, err)
+	}
+	if entries, _ := os.ReadDir(data); len(entries) != 0 {
+		t.Fatalf("expected dry run to write nothing, got %d entries", len(entries))
+	}
+
+	if _, err := InstallSupportFiles(mem, "share", false); err != nil {
+		t.Fatalf("InstallSupportFiles error: %v", err)
+	}
+	for path, content := range want {
+		if got, err := os.ReadFile(path); err != nil || string(got) != content {
+			t.Errorf("expected %s with %q, got %q, %v", path, content, got, err)
+		}
+	}
+
+	removed, err := UninstallSupportFiles(mem, "share", false)
+	if err != nil || len(removed) != len(want) {
+		t.Fatalf("expected %d removed files, got %v, %v", len(want), removed, err)
+	}
+	for path := range want {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s removed, got %v", path, err)
+		}
+	}
+	if removed, _ := UninstallSupportFiles(mem, "share", false); len(removed) != 0 {
+		t.Errorf("expected nothing left to remove, got %v", removed)
+	}
+}
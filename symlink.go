@@ -0,0 +1,222 @@
+package efs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SymlinkFS is implemented by file systems that can report symlink targets,
+// such as DirFS, and by every fs.ReadLinkFS (os.DirFS and fstest.MapFS since
+// Go 1.25). ReadLink returns the target of the named link as stored, without
+// resolving it.
+type SymlinkFS interface {
+	fs.FS
+	ReadLink(name string) (string, error)
+}
+
+// SymlinkFallback decides what WithSymlinks does when a symlink cannot be
+// created because the process lacks the privilege, as is common on Windows
+// without Developer Mode.
+type SymlinkFallback int
+
+const (
+	SymlinkFail     SymlinkFallback = iota // Abort the extraction (default)
+	SymlinkCopy                            // Copy the link target's content instead
+	SymlinkJunction                        // Create a directory junction for directory targets (Windows), else copy
+)
+
+// WithSymlinks recreates symlinks from sources implementing SymlinkFS as
+// symlinks with the same target, instead of copying the content they point
+// to. fallback applies when creating a symlink fails with
+// ERROR_PRIVILEGE_NOT_HELD on Windows. Sources without symlink support are
+// extracted normally.
+//
+// Links must stay inside the extraction: a link whose target is absolute or
+// climbs out of the extraction root with "..", also by way of other links in
+// the tree, fails the extraction with a *SourceError wrapping
+// ErrSymlinkEscape, since later entries could otherwise be written through
+// it to arbitrary places. For the same reason no entry is written through a
+// link that resolves outside the root. WithUnconfinedSymlinks lifts the
+// restriction for trusted sources.
+func WithSymlinks(fallback SymlinkFallback) Option {
+	return func(o *options) {
+		o.symlinks = true
+		o.symlinkFallback = fallback
+	}
+}
+
+// ErrSymlinkEscape is reported under WithSymlinks for a link whose target
+// lies outside the extraction root.
+var ErrSymlinkEscape = errors.New("symlink target escapes the extraction root")
+
+// WithUnconfinedSymlinks lets WithSymlinks recreate links with absolute
+// targets or targets outside the extraction root, such as toolchains
+// pointing into /usr/lib. Use it only for trusted sources.
+func WithUnconfinedSymlinks() Option {
+	return func(o *options) { o.unconfinedSymlinks = true }
+}
+
+// escapes reports whether the link target, stored at destination path rel,
+// points outside the extraction root.
+func escapes(rel, target string) bool {
+	if target == "" || absTarget(target) {
+		return true
+	}
+	return !filepath.IsLocal(filepath.FromSlash(path.Join(path.Dir(rel), filepath.ToSlash(target))))
+}
+
+// absTarget reports whether the link target is absolute on any platform.
+func absTarget(target string) bool {
+	return path.IsAbs(target) || filepath.IsAbs(target) || filepath.VolumeName(target) != ""
+}
+
+// inside reports whether the slash-separated path rel, resolved below root
+// through the symlinks already present on disk, stays inside root. Missing
+// components are taken as the directories they would be created as.
+func inside(root, rel string) bool {
+	var resolved []string // Components below root, free of links
+	pending := strings.Split(rel, "/")
+	for links := 0; len(pending) > 0; {
+		c := pending[0]
+		pending = pending[1:]
+		switch c {
+		case "", ".":
+			continue
+		case "..":
+			if len(resolved) == 0 {
+				return false
+			}
+			resolved = resolved[:len(resolved)-1]
+			continue
+		}
+		p := filepath.Join(root, filepath.Join(resolved...), c)
+		info, err := os.Lstat(p)
+		if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+			resolved = append(resolved, c)
+			continue
+		}
+		target, err := os.Readlink(p)
+		if links++; err != nil || links > 255 || absTarget(target) {
+			return false
+		}
+		pending = append(strings.Split(filepath.ToSlash(target), "/"), pending...)
+	}
+	return true
+}
+
+// isSymlink reports whether d is a symlink that WithSymlinks should preserve.
+func (o *options) isSymlink(fsys fs.FS, d fs.DirEntry) bool {
+	if !o.symlinks || d.Type()&fs.ModeSymlink == 0 {
+		return false
+	}
+	_, ok := fsys.(SymlinkFS)
+	return ok
+}
+
+// extractSymlink recreates the symlink src at dst, applying the fallback
+// policy if that is not permitted.
+func (a *applier) extractSymlink(src, rel, dst string) error {
+	o := a.o
+	target, err := a.fsys.(SymlinkFS).ReadLink(src)
+	if err != nil {
+		return sourceErr(src, err)
+	}
+	if !o.unconfinedSymlinks && (escapes(rel, target) || !inside(a.root, path.Dir(rel)+"/"+target)) {
+		return sourceErr(src, fmt.Errorf("%w: %s -> %s", ErrSymlinkEscape, rel, target))
+	}
+	if err := a.confine(src, path.Dir(rel)); err != nil {
+		return err
+	}
+	if err := a.mkdirAll(filepath.Dir(dst)); err != nil {
+		return destErr(dst, err)
+	}
+	skip, err := o.resolveConflict(dst)
+	if err != nil {
+		return destErr(dst, err)
+	}
+	if skip {
+		a.rep.Skipped = append(a.rep.Skipped, rel)
+		return nil
+	}
+	// os.Symlink does not replace; remove a previous file or link first.
+	if info, err := os.Lstat(dst); err == nil && !info.IsDir() {
+		if err := os.Remove(dst); err != nil {
+			return destErr(dst, err)
+		}
+	}
+
+	a.j.create(dst)
+	err = os.Symlink(filepath.FromSlash(target), dst)
+	if isPrivilegeNotHeld(err) && o.symlinkFallback != SymlinkFail {
+		return a.symlinkFallback(src, rel, dst, target)
+	}
+	if err != nil {
+		return destErr(dst, err)
+	}
+	a.rep.Symlinks++
+	a.links = append(a.links, planEntry{src: src, rel: rel})
+	return destErr(dst, o.applyOwner(dst))
+}
+
+// confine fails with ErrSymlinkEscape if writing the entry src at
+// destination path rel would pass through a link created by WithSymlinks
+// that resolves outside the extraction root.
+func (a *applier) confine(src, rel string) error {
+	if !a.o.symlinks || a.o.unconfinedSymlinks || inside(a.root, rel) {
+		return nil
+	}
+	return sourceErr(src, fmt.Errorf("%w: %s resolves outside the root", ErrSymlinkEscape, rel))
+}
+
+// checkLinks re-resolves the links created so far, since a link created
+// later can change where an earlier one leads (b -> ".", then a -> "b/..").
+func (a *applier) checkLinks() error {
+	if a.o.unconfinedSymlinks {
+		return nil
+	}
+	for _, l := range a.links {
+		target, err := os.Readlink(a.dest(l.rel))
+		if err != nil {
+			continue // Replaced by a later entry
+		}
+		if !inside(a.root, path.Dir(l.rel)+"/"+filepath.ToSlash(target)) {
+			return sourceErr(l.src, fmt.Errorf("%w: %s -> %s", ErrSymlinkEscape, l.rel, target))
+		}
+	}
+	return nil
+}
+
+// symlinkFallback materializes the symlink src whose target could not be
+// linked, by junction or by copying what it points to.
+func (a *applier) symlinkFallback(src, rel, dst, target string) error {
+	info, err := fs.Stat(a.fsys, src)
+	if err != nil {
+		return sourceErr(src, err)
+	}
+	if !info.IsDir() {
+		existed := a.o.existed(dst)
+		n, digest, err := a.writeFile(src, rel, dst)
+		return a.fileWritten(src, rel, dst, existed, n, digest, err)
+	}
+	if a.o.symlinkFallback == SymlinkJunction {
+		abs := filepath.FromSlash(target)
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(filepath.Dir(dst), abs)
+		}
+		if err := createJunction(abs, dst); err == nil {
+			a.rep.Symlinks++
+			return nil
+		} else if !errors.Is(err, errors.ErrUnsupported) {
+			return destErr(dst, err)
+		}
+	}
+	// Copy the directory the link points to, walking it through the link.
+	return walkSource(a.fsys, source{root: src, dest: rel}, a.o, func(p, r string, d fs.DirEntry) error {
+		return a.extractEntry(p, r, d, a.dest(r))
+	})
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package efs
+
+import "errors"
+
+// isPrivilegeNotHeld reports false; creating symlinks needs no privilege here.
+func isPrivilegeNotHeld(err error) bool {
+	return false
+}
+
+// createJunction is only available on Windows.
+func createJunction(target, link string) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,162 @@
+//go:build unix
+
+package efs
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// symlinkTree creates a source tree with a file link and a directory link.
+func symlinkTree(t *testing.T) string {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "data"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "data", "v1.txt"), []byte("V1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("data/v1.txt", filepath.Join(src, "current.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("data", filepath.Join(src, "latest")); err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+func TestWithSymlinks(t *testing.T) {
+	src := symlinkTree(t)
+
+	e, err := Extract(DirFS(src), ".", "links", t.TempDir(), WithSymlinks(SymlinkFail))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+
+	for name, want := range map[string]string{"current.txt": "data/v1.txt", "latest": "data"} {
+		if got, err := os.Readlink(e.Path(name)); err != nil || got != want {
+			t.Errorf("expected %s -> %s, got %q (err=%v)", name, want, got, err)
+		}
+	}
+	if r := e.Report(); r.Symlinks != 2 || r.Files != 1 {
+		t.Errorf("unexpected report %+v", r)
+	}
+	if err := e.Verify(); err != nil {
+		t.Errorf("Verify error: %v", err)
+	}
+}
+
+func TestSymlinkFallbackCopy(t *testing.T) {
+	src := symlinkTree(t)
+	dst := t.TempDir()
+
+	// Junctions are Windows-only, so both policies copy here.
+	for _, policy := range []SymlinkFallback{SymlinkCopy, SymlinkJunction} {
+		var files []string
+		var audit bytes.Buffer
+		var warned bool
+		o := newOptions([]Option{WithSymlinks(policy), WithAuditLog(&audit),
+			WithOnFile(func(rel, path string) { files = append(files, rel) }),
+			WithSoftLimits(0, 1, func(LimitWarning) { warned = true })})
+		a := &applier{fsys: DirFS(src), o: o, rep: &Report{}, limits: &softLimits{}, root: dst}
+		if err := a.symlinkFallback("latest", "latest", a.dest("latest"), "data"); err != nil {
+			t.Fatalf("dir fallback error: %v", err)
+		}
+		if err := a.symlinkFallback("current.txt", "current.txt", a.dest("current.txt"), "data/v1.txt"); err != nil {
+			t.Fatalf("file fallback error: %v", err)
+		}
+		for _, name := range []string{"latest/v1.txt", "current.txt"} {
+			data, err := os.ReadFile(filepath.Join(dst, name))
+			if err != nil || string(data) != "V1" {
+				t.Errorf("expected copied %s, got %q (err=%v)", name, data, err)
+			}
+		}
+		if fi, err := os.Lstat(filepath.Join(dst, "latest")); err != nil || !fi.IsDir() {
+			t.Errorf("expected a real directory, got %v (err=%v)", fi, err)
+		}
+		// Copies are accounted for like any other extracted file.
+		if len(files) != 2 || strings.Count(audit.String(), "\n") != 2 || !warned {
+			t.Errorf("expected 2 callbacks, 2 audit records and a limit warning, got %v, %q, %v", files, audit.String(), warned)
+		}
+	}
+}
+
+func TestSymlinkEscape(t *testing.T) {
+	for _, target := range []string{"../../outside", "../data/../../outside", "/etc"} {
+		src := symlinkTree(t)
+		if err := os.Symlink(target, filepath.Join(src, "data", "escape")); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Extract(DirFS(src), ".", "links", t.TempDir(), WithSymlinks(SymlinkFail))
+		if se := (*SourceError)(nil); !errors.Is(err, ErrSymlinkEscape) || !errors.As(err, &se) {
+			t.Errorf("%s: expected a *SourceError wrapping ErrSymlinkEscape, got %v", target, err)
+		}
+
+		e, err := Extract(DirFS(src), ".", "links", t.TempDir(), WithSymlinks(SymlinkFail), WithUnconfinedSymlinks())
+		if err != nil {
+			t.Fatalf("%s: Extract with WithUnconfinedSymlinks error: %v", target, err)
+		}
+		if got, err := os.Readlink(e.Path("data/escape")); err != nil || got != target {
+			t.Errorf("expected data/escape -> %s, got %q (err=%v)", target, got, err)
+		}
+		e.Cleanup()
+	}
+}
+
+func TestSymlinksFromReadLinkFS(t *testing.T) {
+	mem := fstest.MapFS{
+		"tool/lib/libfoo.so.1": {Data: []byte("ELF")},
+		"tool/lib/libfoo.so":   {Data: []byte("libfoo.so.1"), Mode: fs.ModeSymlink},
+	}
+	if _, ok := fs.FS(mem).(SymlinkFS); !ok {
+		t.Skip("fstest.MapFS does not implement fs.ReadLinkFS before Go 1.25")
+	}
+	e, err := Extract(mem, "tool", "links", t.TempDir(), WithSymlinks(SymlinkFail))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer e.Cleanup()
+	if got, err := os.Readlink(e.Path("lib/libfoo.so")); err != nil || got != "libfoo.so.1" {
+		t.Errorf("expected lib/libfoo.so -> libfoo.so.1, got %q (err=%v)", got, err)
+	}
+}
+
+func TestSymlinkChainEscape(t *testing.T) {
+	mem := fstest.MapFS{
+		"b":        {Data: []byte("."), Mode: fs.ModeSymlink},
+		"a":        {Data: []byte("b/.."), Mode: fs.ModeSymlink},
+		"evil.txt": {Data: []byte("pwned")},
+	}
+	if _, ok := fs.FS(mem).(SymlinkFS); !ok {
+		t.Skip("fstest.MapFS does not implement fs.ReadLinkFS before Go 1.25")
+	}
+	base := t.TempDir()
+	rename := WithRename(func(p string) (string, bool) {
+		if p == "evil.txt" {
+			return "a/evil.txt", false
+		}
+		return p, false
+	})
+
+	// Each target looks local on its own; together a leads out of the root.
+	_, err := Extract(mem, ".", "links", base, WithSymlinks(SymlinkFail), rename)
+	if !errors.Is(err, ErrSymlinkEscape) {
+		t.Errorf("expected ErrSymlinkEscape, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "evil.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected nothing written outside the root, got %v", err)
+	}
+
+	// The chain alone, without a write through it, is refused as well.
+	delete(mem, "evil.txt")
+	if _, err := Extract(mem, ".", "links", base, WithSymlinks(SymlinkFail)); !errors.Is(err, ErrSymlinkEscape) {
+		t.Errorf("expected ErrSymlinkEscape for the link chain, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package efs
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"unicode/utf16"
+)
+
+const errorPrivilegeNotHeld syscall.Errno = 1314 // ERROR_PRIVILEGE_NOT_HELD
+
+const (
+	fsctlSetReparsePoint   = 0x900A4    // FSCTL_SET_REPARSE_POINT
+	ioReparseTagMountPoint = 0xA0000003 // IO_REPARSE_TAG_MOUNT_POINT
+)
+
+// isPrivilegeNotHeld reports whether err means the process may not create
+// symlinks.
+func isPrivilegeNotHeld(err error) bool {
+	return errors.Is(err, errorPrivilegeNotHeld)
+}
+
+// createJunction creates a directory junction at link pointing to the
+// absolute directory target. Junctions need no special privilege. The
+// reparse point is set directly rather than through "mklink /J", as cmd.exe
+// would interpret metacharacters in the names.
+func createJunction(target, link string) error {
+	if err := os.Mkdir(link, 0o777); err != nil {
+		return err
+	}
+	if err := setJunction(target, link); err != nil {
+		os.Remove(link)
+		return &os.LinkError{Op: "junction", Old: target, New: link, Err: err}
+	}
+	return nil
+}
+
+// setJunction turns the empty directory link into a mount point reparse
+// point for target.
+func setJunction(target, link string) error {
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return err
+	}
+	target = strings.TrimPrefix(target, `\\?\`)
+	subst := utf16.Encode([]rune(`\??\` + target))
+	printName := utf16.Encode([]rune(target))
+
+	// REPARSE_DATA_BUFFER with a MountPointReparseBuffer: both names are
+	// NUL-terminated, the lengths exclude the terminators.
+	names := slices.Concat(subst, []uint16{0}, printName, []uint16{0})
+	buf := make([]byte, 16+2*len(names))
+	binary.LittleEndian.PutUint32(buf[0:], ioReparseTagMountPoint)
+	binary.LittleEndian.PutUint16(buf[4:], uint16(8+2*len(names))) // ReparseDataLength
+	binary.LittleEndian.PutUint16(buf[8:], 0)                      // SubstituteNameOffset
+	binary.LittleEndian.PutUint16(buf[10:], uint16(2*len(subst)))
+	binary.LittleEndian.PutUint16(buf[12:], uint16(2*len(subst)+2)) // PrintNameOffset
+	binary.LittleEndian.PutUint16(buf[14:], uint16(2*len(printName)))
+	for i, c := range names {
+		binary.LittleEndian.PutUint16(buf[16+2*i:], c)
+	}
+	if len(buf) > syscall.MAXIMUM_REPARSE_DATA_BUFFER_SIZE {
+		return errors.New("junction target too long")
+	}
+
+	p, err := syscall.UTF16PtrFromString(link)
+	if err != nil {
+		return err
+	}
+	h, err := syscall.CreateFile(p, syscall.GENERIC_WRITE, 0, nil, syscall.OPEN_EXISTING,
+		syscall.FILE_FLAG_OPEN_REPARSE_POINT|syscall.FILE_FLAG_BACKUP_SEMANTICS, 0)
+	if err != nil {
+		return err
+	}
+	defer syscall.CloseHandle(h)
+	var n uint32
+	return syscall.DeviceIoControl(h, fsctlSetReparsePoint, &buf[0], uint32(len(buf)), nil, 0, &n, nil)
+}
@@ -0,0 +1,68 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+)
+
+// SyncResult lists what SyncToDir did with each file of the source, by
+// slash-separated path relative to the destination.
+type SyncResult struct {
+	Created   []string // Files that did not exist in the destination
+	Updated   []string // Existing files whose content differed and was replaced
+	Unchanged []string // Existing files with identical content, left alone
+	Deleted   []string // Entries removed under WithMirror; directories are listed once
+}
+
+// SyncToDir brings dst up to date with root in fsys, writing only the files
+// that are missing or whose content differs. Existing files are compared by
+// size first and by content only when the sizes match, so restarting a
+// server that reuses a persistent asset directory touches nothing that is
+// already current. dst is created if needed; files in dst that are not in
+// the source are kept unless WithMirror is given. It is ExtractTo with
+// ConflictOverwriteIfDifferent and accepts the same options, except that
+// WithConflict is overridden.
+//
+// Example:
+//
+//	res, err := efs.SyncToDir(assets, "assets", "/var/lib/myapp/assets")
+//	if err != nil { return err }
+//	log.Printf("assets: %d created, %d updated", len(res.Created), len(res.Updated))
+func SyncToDir(fsys fs.FS, root string, dst string, opts ...Option) (*SyncResult, error) {
+	res := &SyncResult{}
+	opts = append(slices.Clip(opts), WithConflict(ConflictOverwriteIfDifferent), func(o *options) { o.syncTo = res })
+	if err := ExtractTo(fsys, root, dst, opts...); err != nil {
+		return nil, err
+	}
+	slices.Sort(res.Created)
+	slices.Sort(res.Updated)
+	slices.Sort(res.Unchanged)
+	slices.Sort(res.Deleted)
+	return res, nil
+}
+
+// existed reports whether dst existed before a SyncToDir wrote it; it is
+// only checked when syncing.
+func (o *options) existed(dst string) bool {
+	if o.syncTo == nil {
+		return false
+	}
+	_, err := os.Lstat(dst)
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// recordSync files rel under its SyncResult list. The caller holds the
+// applier's lock.
+func (o *options) recordSync(rel string, existed, unchanged bool) {
+	switch r := o.syncTo; {
+	case r == nil:
+	case unchanged:
+		r.Unchanged = append(r.Unchanged, rel)
+	case existed:
+		r.Updated = append(r.Updated, rel)
+	default:
+		r.Created = append(r.Created, rel)
+	}
+}
@@ -0,0 +1,99 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestSyncToDir(t *testing.T) {
+	mem := fstest.MapFS{
+		"assets/a.txt":   {Data: []byte("A")},
+		"assets/b/c.txt": {Data: []byte("C")},
+		"assets/d.txt":   {Data: []byte("D")},
+	}
+	dst := t.TempDir()
+
+	res, err := SyncToDir(mem, "assets", dst, WithConcurrency(4))
+	if err != nil {
+		t.Fatalf("SyncToDir error: %v", err)
+	}
+	if want := []string{"a.txt", "b/c.txt", "d.txt"}; !slices.Equal(res.Created, want) || len(res.Updated)+len(res.Unchanged) != 0 {
+		t.Errorf("expected %v created, got %+v", want, res)
+	}
+
+	// Modify one file, leave a local extra, and sync again.
+	if err := os.WriteFile(filepath.Join(dst, "d.txt"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dst, "local.txt"), []byte("L"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(filepath.Join(dst, "a.txt"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	res, err = SyncToDir(mem, "assets", dst)
+	if err != nil {
+		t.Fatalf("SyncToDir error: %v", err)
+	}
+	if len(res.Created) != 0 || !slices.Equal(res.Updated, []string{"d.txt"}) || !slices.Equal(res.Unchanged, []string{"a.txt", "b/c.txt"}) {
+		t.Errorf("unexpected result %+v", res)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "a.txt")); err != nil || !info.ModTime().Equal(old) {
+		t.Errorf("expected a.txt untouched, got %v (%v)", info, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dst, "d.txt")); err != nil || string(data) != "D" {
+		t.Errorf("expected d.txt restored, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "local.txt")); err != nil {
+		t.Errorf("expected the local file kept: %v", err)
+	}
+}
+
+func TestWithMirror(t *testing.T) {
+	mem := fstest.MapFS{
+		"assets/a.txt":   {Data: []byte("A")},
+		"assets/b/c.txt": {Data: []byte("C")},
+	}
+	dst := t.TempDir()
+	for _, name := range []string{"stale.txt", "b/old.txt", "gone/x/y.txt"} {
+		p := filepath.Join(dst, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("old"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := SyncToDir(mem, "assets", dst, WithMirror())
+	if err != nil {
+		t.Fatalf("SyncToDir error: %v", err)
+	}
+	if want := []string{"b/old.txt", "gone", "stale.txt"}; !slices.Equal(res.Deleted, want) {
+		t.Errorf("expected %v deleted, got %v", want, res.Deleted)
+	}
+	var got []string
+	filepath.WalkDir(dst, func(p string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dst, p)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if want := []string{"a.txt", "b/c.txt"}; !slices.Equal(got, want) {
+		t.Errorf("expected only %v left, got %v", want, got)
+	}
+
+	// Filtered entries do not belong to the mirror.
+	if err := ExtractTo(mem, "assets", dst, WithMirror(), WithExclude("b")); err != nil {
+		t.Fatalf("ExtractTo error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "b")); !os.IsNotExist(err) {
+		t.Errorf("expected the excluded directory pruned, got %v", err)
+	}
+}
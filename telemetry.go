@@ -0,0 +1,25 @@
+package efs
+
+import "time"
+
+// CleanupStats summarizes the life of an extraction, reported by
+// WithOnCleanup when it is cleaned up.
+type CleanupStats struct {
+	ID           string        // Extraction ID (see WithExtractionID)
+	Path         string        // Extracted directory or file
+	Alive        time.Duration // Time from the start of extraction to cleanup
+	DiskBytes    int64         // Space on disk just before removal, as reported by DiskUsage
+	Files        int           // Regular files extracted
+	VerifyFailed bool          // Whether Verify ever reported a difference or failed
+	Err          error         // Result of the cleanup
+}
+
+// WithOnCleanup calls fn with a CleanupStats summary when the extraction is
+// cleaned up (by Cleanup or the cleanup function of ExtractToTemp and
+// ExtractFile), so operators can feed logs or metrics with how long temp
+// assets actually live and how large they grow. fn is called once, after
+// removal, on the goroutine that cleaned up; an extraction handed over with
+// MoveTo is not reported.
+func WithOnCleanup(fn func(CleanupStats)) Option {
+	return func(o *options) { o.onCleanup = fn }
+}
@@ -0,0 +1,50 @@
+package efs
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithOnCleanup(t *testing.T) {
+	mem := fstest.MapFS{
+		"assets/a.txt": {Data: []byte("AAAA")},
+		"assets/b.txt": {Data: []byte("B")},
+	}
+
+	var got []CleanupStats
+	onCleanup := WithOnCleanup(func(s CleanupStats) { got = append(got, s) })
+	ex, err := Extract(mem, "assets", "tele", t.TempDir(), onCleanup, WithExtractionID("t1"))
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	if err := os.WriteFile(ex.Path("a.txt"), []byte("tampered"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if ex.Verify() == nil {
+		t.Fatal("expected Verify to fail after tampering")
+	}
+	ex.Cleanup()
+	ex.Cleanup()
+
+	if len(got) != 1 {
+		t.Fatalf("expected one summary, got %+v", got)
+	}
+	s := got[0]
+	if s.ID != "t1" || s.Path != ex.Dir() || s.Files != 2 || !s.VerifyFailed || s.Err != nil {
+		t.Errorf("unexpected summary %+v", s)
+	}
+	if s.Alive <= 0 || s.DiskBytes <= 0 {
+		t.Errorf("expected lifetime and disk usage, got %+v", s)
+	}
+
+	got = nil
+	_, cleanup, err := ExtractFile(mem, "assets/a.txt", "tele", t.TempDir(), onCleanup)
+	if err != nil {
+		t.Fatalf("ExtractFile error: %v", err)
+	}
+	cleanup()
+	if len(got) != 1 || got[0].Files != 1 || got[0].VerifyFailed {
+		t.Errorf("unexpected file summary %+v", got)
+	}
+}
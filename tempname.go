@@ -0,0 +1,91 @@
+package efs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidPrefix is reported when a tempPrefix contains a path separator or
+// control character or is longer than maxPrefixLen bytes.
+var ErrInvalidPrefix = errors.New("invalid temp prefix")
+
+// maxPrefixLen leaves room for the random suffix and extension within the
+// common 255-byte file name limit.
+const maxPrefixLen = 128
+
+// validatePrefix checks that prefix can be used as the start of a file name.
+func validatePrefix(prefix string) error {
+	if len(prefix) > maxPrefixLen {
+		return fmt.Errorf("%w: longer than %d bytes", ErrInvalidPrefix, maxPrefixLen)
+	}
+	for _, r := range prefix {
+		switch {
+		case r == '/' || r == '\\':
+			return fmt.Errorf("%w %q: contains a path separator", ErrInvalidPrefix, prefix)
+		case r < 0x20 || r == 0x7f:
+			return fmt.Errorf("%w %q: contains a control character", ErrInvalidPrefix, prefix)
+		}
+	}
+	return nil
+}
+
+// WithNameGenerator replaces the random suffix of temp directory and file
+// names with names produced by fn, which receives the tempPrefix and returns
+// a single path element (e.g. prefix+"-"+hostname+"-"+ulid). This makes it
+// easy to correlate directories with workloads. ExtractFile appends the
+// source extension to the generated name. Names are created exclusively; if
+// one already exists, fn is called again, so it should produce a fresh name
+// each time. Generating the same name twice in a row aborts the extraction.
+func WithNameGenerator(fn func(prefix string) string) Option {
+	return func(o *options) { o.nameGen = fn }
+}
+
+// mkdirTemp creates a new temporary directory in baseDir named after prefix.
+func (o *options) mkdirTemp(baseDir, prefix string) (string, error) {
+	if o.nameGen == nil {
+		return os.MkdirTemp(baseDir, prefix+"-")
+	}
+	return o.createNamed(baseDir, prefix, "", func(path string) error {
+		return os.Mkdir(path, 0o700)
+	})
+}
+
+// createTemp creates a new temporary file in baseDir named after prefix and
+// ending in ext, opened for writing.
+func (o *options) createTemp(baseDir, prefix, ext string) (*os.File, error) {
+	if o.nameGen == nil {
+		return os.CreateTemp(baseDir, prefix+"-*"+ext)
+	}
+	var f *os.File
+	_, err := o.createNamed(baseDir, prefix, ext, func(path string) error {
+		var err error
+		f, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+		return err
+	})
+	return f, err
+}
+
+// createNamed calls create for generated names in baseDir until one does
+// not exist yet, and returns the created path.
+func (o *options) createNamed(baseDir, prefix, suffix string, create func(path string) error) (string, error) {
+	prev := ""
+	for try := 0; ; try++ {
+		name := o.nameGen(prefix) + suffix
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return "", fmt.Errorf("name generator returned invalid name %q", name)
+		}
+		path := filepath.Join(baseDir, name)
+		err := create(path)
+		if err == nil {
+			return path, nil
+		}
+		if !errors.Is(err, fs.ErrExist) || name == prev || try >= 10000 {
+			return "", err
+		}
+		prev = name
+	}
+}
@@ -0,0 +1,81 @@
+package efs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithNameGenerator(t *testing.T) {
+	mem := fstest.MapFS{"cfg/app.json": {Data: []byte("{}")}}
+	base := t.TempDir()
+
+	n := 0
+	gen := WithNameGenerator(func(prefix string) string {
+		n++
+		return fmt.Sprintf("%s-worker7-%d", prefix, n%2)
+	})
+
+	dir, cleanup, err := ExtractToTemp(mem, "cfg", "job", base, gen)
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+	if filepath.Base(dir) != "job-worker7-1" {
+		t.Errorf("unexpected dir name %s", dir)
+	}
+
+	file, fcleanup, err := ExtractFile(mem, "cfg/app.json", "job", base, gen)
+	if err != nil {
+		t.Fatalf("ExtractFile error: %v", err)
+	}
+	defer fcleanup()
+	if filepath.Base(file) != "job-worker7-0.json" {
+		t.Errorf("unexpected file name %s", file)
+	}
+
+	// A collision asks for a new name; repeating the same name gives up.
+	dir2, cleanup2, err := ExtractToTemp(mem, "cfg", "job", base, gen)
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup2()
+	if filepath.Base(dir2) != "job-worker7-0" {
+		t.Errorf("expected collision to be retried, got %s", dir2)
+	}
+	fixed := WithNameGenerator(func(prefix string) string { return prefix + "-fixed" })
+	_, c, err := ExtractToTemp(mem, "cfg", "job", base, fixed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c()
+	if _, _, err := ExtractToTemp(mem, "cfg", "job", base, fixed); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("expected fs.ErrExist for a repeated name, got %v", err)
+	}
+
+	bad := WithNameGenerator(func(prefix string) string { return "../" + prefix })
+	if _, _, err := ExtractToTemp(mem, "cfg", "job", base, bad); err == nil {
+		t.Error("expected invalid generated name to be rejected")
+	}
+}
+
+func TestInvalidPrefix(t *testing.T) {
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+	base := t.TempDir()
+	for _, prefix := range []string{"a/b", `a\b`, "tab\there", strings.Repeat("x", 200)} {
+		if _, _, err := ExtractToTemp(mem, ".", prefix, base); !errors.Is(err, ErrInvalidPrefix) {
+			t.Errorf("ExtractToTemp(%q): expected ErrInvalidPrefix, got %v", prefix, err)
+		}
+		if _, _, err := ExtractFile(mem, "a.txt", prefix, base); !errors.Is(err, ErrInvalidPrefix) {
+			t.Errorf("ExtractFile(%q): expected ErrInvalidPrefix, got %v", prefix, err)
+		}
+	}
+	if entries, _ := os.ReadDir(base); len(entries) != 0 {
+		t.Errorf("expected nothing created, got %v", entries)
+	}
+}
@@ -0,0 +1,32 @@
+package efs
+
+// WithPreferTmpfs extracts into a RAM-backed tmpfs mount on Linux (such as
+// $XDG_RUNTIME_DIR, /run/user/$UID or /dev/shm) that is writable and has
+// room for the whole tree, giving memory-speed access to assets that are
+// read heavily but never need to persist. It takes precedence over
+// DefaultBaseDir, but an explicit tempDir argument or WithTempDir wins. If no
+// suitable tmpfs is found, and on other platforms, the usual base directory
+// is used.
+func WithPreferTmpfs() Option {
+	return func(o *options) { o.preferTmpfs = true }
+}
+
+// tempBase resolves the base directory for a temporary entry that will hold
+// size bytes: next to the WithStageNear destination, $XDG_RUNTIME_DIR or a
+// tmpfs mount if requested and available, otherwise baseDir.
+func (o *options) tempBase(tempDir string, size int64) string {
+	if o.stageNear != "" && tempDir == "" && o.tempDir == "" {
+		return stageBase(o.stageNear)
+	}
+	if o.runtimeDir && tempDir == "" && o.tempDir == "" {
+		if dir, ok := runtimeDir(); ok {
+			return dir
+		}
+	}
+	if o.preferTmpfs && tempDir == "" && o.tempDir == "" {
+		if dir, ok := tmpfsDir(size); ok {
+			return dir
+		}
+	}
+	return o.baseDir(tempDir)
+}
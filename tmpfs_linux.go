@@ -0,0 +1,36 @@
+package efs
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// tmpfsMagic is the f_type statfs reports for tmpfs.
+const tmpfsMagic = 0x01021994
+
+// tmpfsDir returns the first writable tmpfs candidate with at least size
+// bytes available.
+func tmpfsDir(size int64) (string, bool) {
+	candidates := []string{
+		os.Getenv("XDG_RUNTIME_DIR"),
+		"/run/user/" + strconv.Itoa(os.Getuid()),
+		"/dev/shm",
+	}
+	for _, dir := range candidates {
+		if dir == "" {
+			continue
+		}
+		var st syscall.Statfs_t
+		if syscall.Statfs(dir, &st) != nil || st.Type != tmpfsMagic {
+			continue
+		}
+		if syscall.Access(dir, 0x2 /* W_OK */) != nil {
+			continue
+		}
+		if free := int64(st.Bavail) * int64(st.Bsize); free >= size {
+			return dir, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,40 @@
+package efs
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithPreferTmpfs(t *testing.T) {
+	mem := fstest.MapFS{"a.txt": {Data: []byte("A")}}
+
+	if dir, ok := tmpfsDir(math.MaxInt64); ok {
+		t.Errorf("expected no tmpfs with room for MaxInt64 bytes, got %s", dir)
+	}
+
+	want := DefaultBaseDir()
+	if dir, ok := tmpfsDir(1); ok {
+		want = dir
+	}
+	dir, cleanup, err := ExtractToTemp(mem, ".", "tmpfs", "", WithPreferTmpfs())
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+	if filepath.Dir(dir) != want {
+		t.Errorf("expected extraction in %s, got %s", want, dir)
+	}
+
+	// An explicit base directory wins over the preference.
+	base := t.TempDir()
+	dir, cleanup2, err := ExtractToTemp(mem, ".", "tmpfs", base, WithPreferTmpfs())
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup2()
+	if filepath.Dir(dir) != base {
+		t.Errorf("expected extraction in %s, got %s", base, dir)
+	}
+}
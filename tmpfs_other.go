@@ -0,0 +1,8 @@
+//go:build !linux
+
+package efs
+
+// tmpfsDir reports no tmpfs outside Linux.
+func tmpfsDir(size int64) (string, bool) {
+	return "", false
+}
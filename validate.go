@@ -0,0 +1,46 @@
+package efs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// Errors reported by ValidateRoot, wrapped in a *SourceError.
+var (
+	ErrRootNotFound = errors.New("root does not exist")
+	ErrRootNotDir   = errors.New("root is not a directory")
+	ErrRootEmpty    = errors.New("root is empty")
+)
+
+// ValidateRoot checks that root exists in fsys, is a directory and has at
+// least one entry. Call it at startup to fail fast with a clear message when
+// an embed directive or root path is wrong:
+//
+//	if err := efs.ValidateRoot(assets, "assets"); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// A missing root matches both ErrRootNotFound and fs.ErrNotExist.
+func ValidateRoot(fsys fs.FS, root string) error {
+	if root == "" {
+		root = "."
+	}
+	info, err := fs.Stat(fsys, root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &SourceError{Path: root, Err: fmt.Errorf("%w: %w", ErrRootNotFound, err)}
+	} else if err != nil {
+		return sourceErr(root, err)
+	}
+	if !info.IsDir() {
+		return &SourceError{Path: root, Err: fmt.Errorf("%w: %s", ErrRootNotDir, root)}
+	}
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return sourceErr(root, err)
+	}
+	if len(entries) == 0 {
+		return &SourceError{Path: root, Err: fmt.Errorf("%w: %s", ErrRootEmpty, root)}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package efs
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestValidateRoot(t *testing.T) {
+	mem := fstest.MapFS{
+		"assets/a.txt": {Data: []byte("A")},
+		"file.txt":     {Data: []byte("F")},
+		"empty":        {Mode: fs.ModeDir},
+	}
+
+	if err := ValidateRoot(mem, "assets"); err != nil {
+		t.Errorf("expected valid root, got %v", err)
+	}
+
+	tests := []struct {
+		root string
+		want error
+	}{
+		{"missing", ErrRootNotFound},
+		{"missing", fs.ErrNotExist},
+		{"file.txt", ErrRootNotDir},
+		{"empty", ErrRootEmpty},
+	}
+	for _, tt := range tests {
+		err := ValidateRoot(mem, tt.root)
+		var se *SourceError
+		if !errors.Is(err, tt.want) || !errors.As(err, &se) {
+			t.Errorf("ValidateRoot(%q): expected %v in a *SourceError, got %v", tt.root, tt.want, err)
+		}
+	}
+}
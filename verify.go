@@ -0,0 +1,134 @@
+package efs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// VerifyError is returned when an extracted tree no longer matches its source.
+type VerifyError struct {
+	Changes []Change // Ordered by path
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("extracted tree differs from source: %d change(s), first: %s %s",
+		len(e.Changes), e.Changes[0].Path, e.Changes[0].Kind)
+}
+
+// Verify re-walks root in fsys and compares it with dir, a tree extracted
+// from it earlier, for servers that suspect tampering with their assets. It
+// returns every file that is missing from dir, differs from the source in
+// size or content, or exists in dir without a source counterpart, ordered by
+// path; an intact tree yields none. Pass the options that shaped the
+// extraction, such as WithInclude, WithRename or WithSymlinks, so the same
+// files are expected. Bookkeeping files written by efs are not reported.
+// Extraction.Verify does the same for an Extraction handle.
+func Verify(dir string, fsys fs.FS, root string, opts ...Option) ([]Change, error) {
+	if root == "" {
+		root = "."
+	}
+	return verifyTree(fsys, []source{{root: root, dest: "."}}, dir, newOptions(opts))
+}
+
+// verifyTree compares the files of sources in fsys with those in dir.
+func verifyTree(fsys fs.FS, sources []source, dir string, o *options) ([]Change, error) {
+	var changes []Change
+	expected := make(map[string]bool)
+	var copied []string // Symlinked directories that were copied as a fallback
+	for _, src := range sources {
+		err := walkSource(fsys, src, o, func(p, rel string, d fs.DirEntry) error {
+			if o.isReparse(fsys, d) {
+				if o.reparse == ReparseJunction {
+					// A recreated junction; its target is not tracked.
+					copied = append(copied, rel+"/")
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			expected[rel] = true
+			if shim := o.shimName(rel); shim != "" {
+				expected[shim] = true
+			}
+
+			if o.isSymlink(fsys, d) {
+				info, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(rel)))
+				switch {
+				case errors.Is(err, fs.ErrNotExist):
+					changes = append(changes, Change{Path: rel, Kind: ChangeMissing})
+					return nil
+				case err != nil:
+					return err
+				case info.Mode()&fs.ModeSymlink != 0:
+					want, err := fsys.(SymlinkFS).ReadLink(p)
+					if err != nil {
+						return sourceErr(p, err)
+					}
+					got, err := os.Readlink(filepath.Join(dir, filepath.FromSlash(rel)))
+					if err != nil {
+						return err
+					}
+					if filepath.ToSlash(got) != want {
+						changes = append(changes, Change{Path: rel, Kind: ChangeModified})
+					}
+					return nil
+				case info.IsDir():
+					// A linked directory copied as a fallback; its
+					// content is not tracked.
+					copied = append(copied, rel+"/")
+					return nil
+				}
+				// A linked file copied as a fallback compares by content.
+			}
+
+			dst := filepath.Join(dir, filepath.FromSlash(rel))
+			if _, err := os.Lstat(dst); errors.Is(err, fs.ErrNotExist) {
+				changes = append(changes, Change{Path: rel, Kind: ChangeMissing})
+				return nil
+			} else if err != nil {
+				return err
+			}
+			same, err := o.unchanged(fsys, p, dst)
+			if err != nil {
+				return err
+			}
+			if !same {
+				changes = append(changes, Change{Path: rel, Kind: ChangeModified})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if slices.ContainsFunc(copied, func(prefix string) bool { return strings.HasPrefix(rel, prefix) }) {
+			return nil
+		}
+		if !expected[rel] && !reserved(rel) {
+			changes = append(changes, Change{Path: rel, Kind: ChangeExtra})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int { return strings.Compare(a.Path, b.Path) })
+	return changes, nil
+}
@@ -0,0 +1,51 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestVerify(t *testing.T) {
+	mem := fstest.MapFS{
+		"assets/a.txt":     {Data: []byte("A")},
+		"assets/b.txt":     {Data: []byte("B")},
+		"assets/sub/c.txt": {Data: []byte("C")},
+		"assets/skip.log":  {Data: []byte("L")},
+	}
+	dst := t.TempDir()
+	if err := ExtractTo(mem, "assets", dst, WithExclude("*.log")); err != nil {
+		t.Fatalf("ExtractTo error: %v", err)
+	}
+
+	changes, err := Verify(dst, mem, "assets", WithExclude("*.log"))
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("expected intact tree, got %v, %v", changes, err)
+	}
+
+	// A longer file and a same-size edit are both modifications.
+	os.WriteFile(filepath.Join(dst, "a.txt"), []byte("AA"), 0o644)
+	os.WriteFile(filepath.Join(dst, "b.txt"), []byte("X"), 0o644)
+	os.Remove(filepath.Join(dst, "sub", "c.txt"))
+	os.WriteFile(filepath.Join(dst, "new.txt"), []byte("N"), 0o644)
+
+	changes, err = Verify(dst, mem, "assets", WithExclude("*.log"))
+	if err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	want := []Change{
+		{Path: "a.txt", Kind: ChangeModified},
+		{Path: "b.txt", Kind: ChangeModified},
+		{Path: "new.txt", Kind: ChangeExtra},
+		{Path: "sub/c.txt", Kind: ChangeMissing},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %v, got %v", want, changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d: expected %v, got %v", i, want[i], changes[i])
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package efs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+)
+
+// Watch checks every interval whether entries of the extraction have been
+// deleted from underneath the running application, as tmp reapers such as
+// systemd-tmpfiles or cron jobs do with old files in /tmp. When entries are
+// missing and heal is true, they are re-extracted from the source (including
+// the extraction root itself, if it was removed wholesale). onMissing, if not
+// nil, is then called with the slash-separated paths that were missing,
+// ordered as extracted. Without heal, it is called again only when the set
+// of missing entries changes, so a deletion is reported once.
+//
+// Checks only stat the entries; modified content is not detected (see Guard
+// for that). Watch blocks; run it in its own goroutine. It returns ctx.Err()
+// once ctx is done, nil once the extraction has been cleaned up or moved, or
+// the first error that prevents a check or a repair.
+func (e *Extraction) Watch(ctx context.Context, interval time.Duration, heal bool, onMissing func([]string)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last []string
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		missing, done, err := e.checkMissing(ctx, heal)
+		if done || err != nil {
+			return err
+		}
+		if len(missing) > 0 && onMissing != nil && (heal || !slices.Equal(missing, last)) {
+			onMissing(missing)
+		}
+		last = missing
+	}
+}
+
+// checkMissing returns the paths of extracted entries that no longer exist,
+// re-extracting them if heal is set. done reports that the extraction is
+// gone for good, after Cleanup or MoveTo.
+func (e *Extraction) checkMissing(ctx context.Context, heal bool) (missing []string, done bool, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.done {
+		return nil, true, nil
+	}
+
+	var lost []planEntry
+	for _, entry := range e.entries {
+		if e.pending(entry.rel) {
+			continue // Not extracted yet (WithPriority)
+		}
+		_, err := os.Lstat(e.Path(entry.rel))
+		if errors.Is(err, fs.ErrNotExist) {
+			lost = append(lost, entry)
+			missing = append(missing, entry.rel)
+		} else if err != nil {
+			return nil, false, destErr(e.Path(entry.rel), err)
+		}
+	}
+	if len(lost) == 0 || !heal {
+		return missing, false, nil
+	}
+
+	if err := os.MkdirAll(e.dir, e.o.dirPerm()); err != nil {
+		return nil, false, destErr(e.dir, err)
+	}
+	a := &applier{fsys: e.fsys, o: e.o, rep: &Report{}}
+	return missing, false, a.apply(ctx, lost, e.dir)
+}
@@ -0,0 +1,79 @@
+package efs
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestWatchHeals(t *testing.T) {
+	mem := fstest.MapFS{
+		"root/a.txt":     {Data: []byte("A")},
+		"root/sub/b.txt": {Data: []byte("B")},
+	}
+	ex, err := Extract(mem, "root", "watch", t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	reported := make(chan []string, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- ex.Watch(ctx, 5*time.Millisecond, true, func(missing []string) { reported <- missing })
+	}()
+
+	os.RemoveAll(ex.Path("sub")) // A reaper removing a whole subtree
+	select {
+	case missing := <-reported: // May catch the removal halfway
+		if !slices.Contains(missing, "sub/b.txt") {
+			t.Errorf("unexpected missing entries %v", missing)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deletion not detected")
+	}
+	if data, err := os.ReadFile(ex.Path("sub/b.txt")); err != nil || string(data) != "B" {
+		t.Fatalf("expected sub/b.txt to be restored, got %q, %v", data, err)
+	}
+
+	if err := ex.Cleanup(); err != nil {
+		t.Fatalf("Cleanup error: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected Watch to end with nil after Cleanup, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not stop after Cleanup")
+	}
+	if _, err := os.Stat(ex.Dir()); !os.IsNotExist(err) {
+		t.Errorf("expected Watch not to recreate a cleaned up tree, got %v", err)
+	}
+}
+
+func TestWatchReportsOnce(t *testing.T) {
+	mem := fstest.MapFS{"root/a.txt": {Data: []byte("A")}}
+	ex, err := Extract(mem, "root", "watch", t.TempDir())
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+	os.Remove(ex.Path("a.txt"))
+
+	calls := 0
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	ex.Watch(ctx, time.Millisecond, false, func([]string) { calls++ })
+	if calls != 1 {
+		t.Errorf("expected one report of an unhealed deletion, got %d", calls)
+	}
+	if _, err := os.Stat(ex.Path("a.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected no repair without heal, got %v", err)
+	}
+}
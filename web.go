@@ -0,0 +1,63 @@
+package efs
+
+import (
+	"io/fs"
+	"net/http"
+)
+
+// WebAssets is the on-disk copy of a web application's templates and static
+// files, as extracted by ExtractWebAssets.
+type WebAssets struct {
+	TemplateDir string       // Directory holding the templates; "" if none were extracted
+	StaticDir   string       // Directory holding the static files; "" if none were extracted
+	Static      http.Handler // Serves StaticDir; strip the URL prefix it is mounted under
+
+	ex *Extraction
+}
+
+// ExtractWebAssets extracts the template root and the static root of fsys
+// into one new temporary directory and returns their locations together with
+// a file server for the static files, the wiring every web application that
+// embeds its assets repeats. Either root may be empty to leave it out. The
+// temporary directory is created as by ExtractToTemp with the prefix "web";
+// Cleanup removes both trees at once.
+//
+// Example:
+//
+//	web, err := efs.ExtractWebAssets(assets, "templates", "static")
+//	if err != nil { return err }
+//	defer web.Cleanup()
+//	tmpl := template.Must(template.ParseGlob(filepath.Join(web.TemplateDir, "*.html")))
+//	http.Handle("/static/", http.StripPrefix("/static/", web.Static))
+func ExtractWebAssets(fsys fs.FS, templatesRoot, staticRoot string, opts ...Option) (*WebAssets, error) {
+	var roots []string
+	for _, r := range []string{templatesRoot, staticRoot} {
+		if r != "" {
+			roots = append(roots, r)
+		}
+	}
+	sources, err := rootSources("extract web assets", roots)
+	if err != nil {
+		return nil, err
+	}
+	ex, err := extract(fsys, sources, "web", "", newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	w := &WebAssets{ex: ex, Static: http.NotFoundHandler()}
+	if templatesRoot != "" {
+		w.TemplateDir = ex.Path(sources[0].dest)
+	}
+	if staticRoot != "" {
+		w.StaticDir = ex.Path(sources[len(sources)-1].dest)
+		w.Static = http.FileServer(http.Dir(w.StaticDir))
+	}
+	return w, nil
+}
+
+// Cleanup removes the extracted templates and static files. Like
+// Extraction.Cleanup it is idempotent.
+func (w *WebAssets) Cleanup() error {
+	return w.ex.Cleanup()
+}
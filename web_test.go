@@ -0,0 +1,55 @@
+package efs
+
+import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExtractWebAssets(t *testing.T) {
+	SetDefaultBaseDir(t.TempDir())
+	defer SetDefaultBaseDir("")
+	mem := fstest.MapFS{
+		"web/templates/index.html": {Data: []byte("<h1>{{.}}</h1>")},
+		"web/static/app.css":       {Data: []byte("body{}")},
+	}
+
+	w, err := ExtractWebAssets(mem, "web/templates", "web/static")
+	if err != nil {
+		t.Fatalf("ExtractWebAssets error: %v", err)
+	}
+	defer w.Cleanup()
+
+	if _, err := os.Stat(filepath.Join(w.TemplateDir, "index.html")); err != nil {
+		t.Errorf("expected index.html in TemplateDir, got %v", err)
+	}
+	rec := httptest.NewRecorder()
+	w.Static.ServeHTTP(rec, httptest.NewRequest("GET", "/app.css", nil))
+	if body, _ := io.ReadAll(rec.Body); rec.Code != 200 || string(body) != "body{}" {
+		t.Errorf("expected app.css from Static, got %d %q", rec.Code, body)
+	}
+
+	if err := w.Cleanup(); err != nil {
+		t.Fatalf("Cleanup error: %v", err)
+	}
+	if _, err := os.Stat(w.StaticDir); !os.IsNotExist(err) {
+		t.Errorf("expected static files removed, got %v", err)
+	}
+
+	// Only static files.
+	w, err = ExtractWebAssets(mem, "", "web/static")
+	if err != nil {
+		t.Fatalf("ExtractWebAssets error: %v", err)
+	}
+	defer w.Cleanup()
+	if w.TemplateDir != "" || w.StaticDir == "" {
+		t.Errorf("unexpected dirs %q, %q", w.TemplateDir, w.StaticDir)
+	}
+
+	if _, err := ExtractWebAssets(mem, "web", "web/static"); err == nil {
+		t.Error("expected overlapping roots to be rejected")
+	}
+}
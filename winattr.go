@@ -0,0 +1,31 @@
+package efs
+
+// WithWindowsAttributes sets the Windows hidden and/or system attribute on
+// extracted files and directories whose destination path matches one of
+// patterns, so extracted trees look native to users browsing them in
+// Explorer. Patterns use path.Match syntax with "**" matching any number of
+// directories; a pattern without "/" matches the base name at any depth.
+// Without patterns, dotfiles (".*") are matched. WithWindowsAttributes is a
+// no-op on other platforms.
+//
+// Example:
+//
+//	efs.ExtractTo(assets, "app", dir, efs.WithWindowsAttributes(true, false))
+func WithWindowsAttributes(hidden, system bool, patterns ...string) Option {
+	if len(patterns) == 0 {
+		patterns = []string{".*"}
+	}
+	return func(o *options) {
+		o.winHidden, o.winSystem = hidden, system
+		o.winPatterns = patterns
+	}
+}
+
+// applyWindowsAttributes sets the configured attributes on dst if its
+// destination path rel matches.
+func (o *options) applyWindowsAttributes(rel, dst string) error {
+	if !o.winHidden && !o.winSystem || !matchAny(o.winPatterns, rel) {
+		return nil
+	}
+	return setWindowsAttributes(dst, o.winHidden, o.winSystem)
+}
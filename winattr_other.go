@@ -0,0 +1,8 @@
+//go:build !windows
+
+package efs
+
+// setWindowsAttributes is a no-op outside Windows.
+func setWindowsAttributes(path string, hidden, system bool) error {
+	return nil
+}
@@ -0,0 +1,28 @@
+package efs
+
+import (
+	"os"
+	"syscall"
+)
+
+// setWindowsAttributes adds the hidden and/or system attribute to path.
+func setWindowsAttributes(path string, hidden, system bool) error {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return err
+	}
+	attrs, err := syscall.GetFileAttributes(p)
+	if err != nil {
+		return &os.PathError{Op: "getfileattributes", Path: path, Err: err}
+	}
+	if hidden {
+		attrs |= syscall.FILE_ATTRIBUTE_HIDDEN
+	}
+	if system {
+		attrs |= syscall.FILE_ATTRIBUTE_SYSTEM
+	}
+	if err := syscall.SetFileAttributes(p, attrs); err != nil {
+		return &os.PathError{Op: "setfileattributes", Path: path, Err: err}
+	}
+	return nil
+}
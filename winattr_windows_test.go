@@ -0,0 +1,38 @@
+package efs
+
+import (
+	"path/filepath"
+	"syscall"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithWindowsAttributes(t *testing.T) {
+	mem := fstest.MapFS{
+		"app/.config/settings": {Data: []byte("S")},
+		"app/readme.txt":       {Data: []byte("R")},
+	}
+	dir, cleanup, err := ExtractToTemp(mem, "app", "winattr", t.TempDir(), WithWindowsAttributes(true, false))
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+
+	attrs := func(name string) uint32 {
+		p, err := syscall.UTF16PtrFromString(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, err := syscall.GetFileAttributes(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	if attrs(".config")&syscall.FILE_ATTRIBUTE_HIDDEN == 0 {
+		t.Error("expected .config to be hidden")
+	}
+	if attrs("readme.txt")&syscall.FILE_ATTRIBUTE_HIDDEN != 0 {
+		t.Error("expected readme.txt to stay visible")
+	}
+}
@@ -0,0 +1,50 @@
+package efs
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// XattrFS is implemented by file systems that can report extended attributes,
+// such as DirFS or a tar-backed fs.FS. Xattrs returns the attributes of the
+// named file keyed by their full name (e.g. "user.origin").
+type XattrFS interface {
+	fs.FS
+	Xattrs(name string) (map[string][]byte, error)
+}
+
+// userXattrPrefix limits preservation to the unprivileged user namespace;
+// security.* and trusted.* attributes are never copied.
+const userXattrPrefix = "user."
+
+// WithXattrs preserves user.* extended attributes on extracted files and
+// directories when the source implements XattrFS. Sources without xattr
+// support are extracted normally. Attributes are only written on Linux; on
+// other platforms the option has no effect.
+func WithXattrs() Option {
+	return func(o *options) { o.xattrs = true }
+}
+
+// applyXattrs copies the user.* attributes of src in fsys onto dst.
+func (o *options) applyXattrs(fsys fs.FS, src, dst string) error {
+	if !o.xattrs {
+		return nil
+	}
+	xfs, ok := fsys.(XattrFS)
+	if !ok {
+		return nil
+	}
+	attrs, err := xfs.Xattrs(src)
+	if err != nil {
+		return sourceErr(src, err)
+	}
+	for name, value := range attrs {
+		if !strings.HasPrefix(name, userXattrPrefix) {
+			continue
+		}
+		if err := writeXattr(dst, name, value); err != nil {
+			return &fs.PathError{Op: "setxattr", Path: dst, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package efs
+
+import (
+	"bytes"
+	"errors"
+	"syscall"
+)
+
+// readXattrs lists and reads all extended attributes of path. File systems
+// without xattr support yield an empty result rather than an error.
+func readXattrs(path string) (map[string][]byte, error) {
+	size, err := syscall.Listxattr(path, nil)
+	if err != nil {
+		if errors.Is(err, syscall.ENOTSUP) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	buf := make([]byte, size)
+	size, err = syscall.Listxattr(path, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := make(map[string][]byte)
+	for _, name := range bytes.Split(buf[:size], []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		vsize, err := syscall.Getxattr(path, string(name), nil)
+		if err != nil {
+			return nil, err
+		}
+		value := make([]byte, vsize)
+		if vsize > 0 {
+			if vsize, err = syscall.Getxattr(path, string(name), value); err != nil {
+				return nil, err
+			}
+		}
+		attrs[string(name)] = value[:vsize]
+	}
+	return attrs, nil
+}
+
+func writeXattr(path, name string, value []byte) error {
+	return syscall.Setxattr(path, name, value, 0)
+}
@@ -0,0 +1,34 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestWithXattrsPreservesUserAttributes(t *testing.T) {
+	sourceDir := t.TempDir()
+	src := filepath.Join(sourceDir, "asset.bin")
+	if err := os.WriteFile(src, []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Setxattr(src, "user.origin", []byte("pipeline"), 0); err != nil {
+		t.Skipf("user xattrs not supported here: %v", err)
+	}
+
+	dir, cleanup, err := ExtractToTemp(DirFS(sourceDir), ".", "xattr", t.TempDir(), WithXattrs())
+	if err != nil {
+		t.Fatalf("ExtractToTemp error: %v", err)
+	}
+	defer cleanup()
+
+	buf := make([]byte, 64)
+	n, err := syscall.Getxattr(filepath.Join(dir, "asset.bin"), "user.origin", buf)
+	if err != nil {
+		t.Fatalf("expected user.origin on extracted file: %v", err)
+	}
+	if got := string(buf[:n]); got != "pipeline" {
+		t.Errorf("expected %q, got %q", "pipeline", got)
+	}
+}
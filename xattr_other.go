@@ -0,0 +1,13 @@
+//go:build !linux
+
+package efs
+
+// readXattrs reports no attributes on platforms without xattr support in the
+// standard library.
+func readXattrs(path string) (map[string][]byte, error) {
+	return nil, nil
+}
+
+func writeXattr(path, name string, value []byte) error {
+	return nil
+}
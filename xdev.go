@@ -0,0 +1,137 @@
+package efs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// moveTree moves the completed tree staging to dst, replacing any existing
+// tree at dst. It renames when possible. When staging lives on another file
+// system, the tree is first copied (and synced) into a fresh staging
+// directory next to dst, which is then swapped in the same way, so dst ends
+// up either fully replaced or untouched. staging is removed on success.
+func moveTree(staging, dst string, perm fs.FileMode) error {
+	err := commitStaging(staging, dst)
+	if !isCrossDevice(err) {
+		return err
+	}
+	near, err := mkdirUnique(filepath.Dir(dst), "."+filepath.Base(dst)+".efs-staging-", perm)
+	if err != nil {
+		return err
+	}
+	if err := copyTree(staging, near); err != nil {
+		_ = os.RemoveAll(near)
+		return err
+	}
+	if err := commitStaging(near, dst); err != nil {
+		_ = os.RemoveAll(near)
+		return err
+	}
+	return os.RemoveAll(staging)
+}
+
+// copyTree copies the contents of the directory src into the existing
+// directory dst, keeping permission bits and symlinks. Files and directories
+// are synced so the copy is durable before it replaces anything. Ownership
+// and extended attributes are not carried over.
+func copyTree(src, dst string) error {
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			if rel != "." {
+				if err := os.Mkdir(target, 0o700); err != nil {
+					return err
+				}
+			}
+			// Directories get their final mode once their content is in
+			// place (below), so read-only directories can still be filled.
+			return nil
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(p, target, info.Mode().Perm())
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return syncDirModes(src, dst)
+}
+
+// copyFile copies the regular file src to the new file dst with mode perm
+// and syncs it.
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err == nil {
+		err = out.Chmod(perm) // Not subject to the umask
+	}
+	if err == nil {
+		err = out.Sync()
+	}
+	return errors.Join(err, out.Close())
+}
+
+// syncDirModes gives every directory copied from src to dst the mode of its
+// original and syncs it, deepest first.
+func syncDirModes(src, dst string) error {
+	var dirs []string
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			dirs = append(dirs, p)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		info, err := os.Stat(dirs[i])
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, dirs[i])
+		target := filepath.Join(dst, rel)
+		if err := os.Chmod(target, info.Mode().Perm()); err != nil {
+			return err
+		}
+		syncDir(target)
+	}
+	return nil
+}
+
+// syncDir flushes the directory entries of dir to disk where the platform
+// supports it; failures are ignored.
+func syncDir(dir string) {
+	if f, err := os.Open(dir); err == nil {
+		_ = f.Sync()
+		f.Close()
+	}
+}
@@ -0,0 +1,48 @@
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMoveToCrossDevice(t *testing.T) {
+	var shm, tmp syscall.Stat_t
+	base := t.TempDir()
+	if syscall.Stat("/dev/shm", &shm) != nil || syscall.Stat(base, &tmp) != nil || shm.Dev == tmp.Dev {
+		t.Skip("need /dev/shm on a different file system than the temp dir")
+	}
+	if syscall.Access("/dev/shm", 0x2 /* W_OK */) != nil {
+		t.Skip("/dev/shm not writable")
+	}
+
+	mem := fstest.MapFS{"root/sub/a.txt": {Data: []byte("A")}}
+	ex, err := Extract(mem, "root", "xdev", "/dev/shm")
+	if err != nil {
+		t.Fatalf("Extract error: %v", err)
+	}
+	defer ex.Cleanup()
+
+	final := filepath.Join(base, "assets")
+	os.MkdirAll(final, 0o755)
+	os.WriteFile(filepath.Join(final, "old.txt"), []byte("old"), 0o644)
+
+	if err := ex.MoveTo(final); err != nil {
+		t.Fatalf("MoveTo across devices error: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(final, "sub", "a.txt")); err != nil || string(data) != "A" {
+		t.Fatalf("expected copied sub/a.txt, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(final, "old.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected previous tree to be replaced, got %v", err)
+	}
+	if _, err := os.Stat(ex.Dir()); !os.IsNotExist(err) {
+		t.Errorf("expected source tree on /dev/shm to be removed, got %v", err)
+	}
+	entries, _ := os.ReadDir(base)
+	if len(entries) != 1 {
+		t.Errorf("expected only the destination in %s, got %d entries", base, len(entries))
+	}
+}
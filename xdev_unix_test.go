@@ -0,0 +1,32 @@
+//go:build unix
+
+package efs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyTree(t *testing.T) {
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "sub"), 0o755)
+	os.WriteFile(filepath.Join(src, "sub", "a.txt"), []byte("A"), 0o640)
+	os.Chmod(filepath.Join(src, "sub"), 0o500)
+	defer os.Chmod(filepath.Join(src, "sub"), 0o755)
+
+	dst := t.TempDir()
+	if err := copyTree(src, dst); err != nil {
+		t.Fatalf("copyTree error: %v", err)
+	}
+	defer os.Chmod(filepath.Join(dst, "sub"), 0o755)
+
+	if data, err := os.ReadFile(filepath.Join(dst, "sub", "a.txt")); err != nil || string(data) != "A" {
+		t.Fatalf("expected copied a.txt, got %q, %v", data, err)
+	}
+	srcInfo, _ := os.Stat(filepath.Join(src, "sub"))
+	dstInfo, err := os.Stat(filepath.Join(dst, "sub"))
+	if err != nil || dstInfo.Mode().Perm() != srcInfo.Mode().Perm() {
+		t.Errorf("expected dir mode %v, got %v (%v)", srcInfo.Mode().Perm(), dstInfo.Mode().Perm(), err)
+	}
+}
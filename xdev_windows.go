@@ -0,0 +1,15 @@
+package efs
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errorNotSameDevice is ERROR_NOT_SAME_DEVICE, returned by MoveFileEx when
+// source and target are on different volumes.
+const errorNotSameDevice syscall.Errno = 17
+
+// isCrossDevice reports whether err is a rename across file systems.
+func isCrossDevice(err error) bool {
+	return errors.Is(err, errorNotSameDevice)
+}